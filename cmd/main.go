@@ -2,7 +2,10 @@ package main
 
 import (
 	"app/cmd/migrate"
+	"app/cmd/rabbitmq"
+	"app/cmd/scheduler"
 	"app/cmd/server"
+	"app/cmd/worker"
 	"app/internal/web"
 	"log"
 	"os"
@@ -28,5 +31,8 @@ func main() {
 
 	server.Register(rootCmd, web.BuildFS, web.IndexPage)
 	migrate.Register(rootCmd)
+	rabbitmq.Register(rootCmd)
+	scheduler.Register(rootCmd)
+	worker.Register(rootCmd)
 	rootCmd.Execute()
 }
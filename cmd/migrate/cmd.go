@@ -1,16 +1,18 @@
 package migrate
 
 import (
-	"database/sql"
+	"context"
 	"fmt"
 	"log"
+	"time"
 
-	_ "github.com/go-sql-driver/mysql"
 	"github.com/pressly/goose/v3"
 	"github.com/spf13/cobra"
 
-	_ "app/db/migrations" // 导入迁移文件
+	_ "app/db/migrations"      // 导入迁移文件
+	_ "app/db/mongomigrations" // 导入 MongoDB 迁移文件
 	"app/internal/initialization"
+	"app/pkg/mongomigrate"
 )
 
 var upCmd = &cobra.Command{
@@ -81,34 +83,50 @@ func runMigration(command string) error {
 	config := initialization.LoadConfig(cfg)
 
 	// 检查业务数据库配置
-	if config.DbHost == "" || config.DbDatabase == "" {
+	if !config.DefaultDatabaseConfig().Configured() {
 		return fmt.Errorf("业务数据库配置为空, 请检查 DB_* 配置项")
 	}
 
-	// 构建 DSN (使用业务数据库配置)
-	dsn := fmt.Sprintf(
-		"%s:%s@tcp(%s:%d)/%s?parseTime=true",
-		config.DbUsername,
-		config.DbPassword,
-		config.DbHost,
-		config.DbPort,
-		config.DbDatabase,
-	)
-
-	fmt.Printf("📦 使用业务数据库: %s@%s:%d/%s\n",
-		config.DbUsername, config.DbHost, config.DbPort, config.DbDatabase)
-
-	// 打开数据库连接
-	db, err := sql.Open("mysql", dsn)
+	// 按 DB_TYPE 连接业务数据库 (与 server 使用相同的连接配置)
+	// 其他命名连接失败不影响迁移
+	if err := initialization.InitDatabaseConnection(); err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+	defer initialization.CloseDatabaseConnection(context.Background())
+
+	switch {
+	case initialization.MongoDB != nil:
+		return runMongoMigration(command)
+	case initialization.Db != nil:
+		return runSQLMigration(command)
+	default:
+		return fmt.Errorf("连接业务数据库失败")
+	}
+}
+
+// gooseDialects GORM 方言对应的 goose 方言
+var gooseDialects = map[string]string{
+	"mysql":    "mysql",
+	"postgres": "postgres",
+	"sqlite":   "sqlite3",
+}
+
+// runSQLMigration 使用 goose 执行 db/migrations 中的迁移
+func runSQLMigration(command string) error {
+	db, err := initialization.Db.DB()
 	if err != nil {
-		return fmt.Errorf("连接数据库失败: %w", err)
+		return fmt.Errorf("获取数据库连接失败: %w", err)
 	}
-	defer db.Close()
 
-	// 设置 goose 使用 MySQL 方言
-	if err := goose.SetDialect("mysql"); err != nil {
+	name := initialization.Db.Dialector.Name()
+	dialect, ok := gooseDialects[name]
+	if !ok {
+		return fmt.Errorf("不支持的数据库类型: %s", name)
+	}
+	if err := goose.SetDialect(dialect); err != nil {
 		return fmt.Errorf("设置数据库方言失败: %w", err)
 	}
+	fmt.Printf("📦 使用业务数据库: %s %s\n", name, initialization.AppConfig.DbDatabase)
 
 	// 执行迁移命令
 	switch command {
@@ -124,3 +142,36 @@ func runMigration(command string) error {
 		return fmt.Errorf("未知命令: %s", command)
 	}
 }
+
+// runMongoMigration 执行 db/mongomigrations 中的迁移, 版本记录在 schema_migrations 集合
+func runMongoMigration(command string) error {
+	ctx := context.Background()
+	db := initialization.MongoDB
+	fmt.Printf("📦 使用业务数据库: mongodb %s\n", db.Name())
+
+	switch command {
+	case "up":
+		return mongomigrate.Up(ctx, db)
+	case "down":
+		return mongomigrate.Down(ctx, db)
+	case "status":
+		migrations, err := mongomigrate.Status(ctx, db)
+		if err != nil {
+			return err
+		}
+		fmt.Println("    Applied At                  Migration")
+		fmt.Println("    =======================================")
+		for _, m := range migrations {
+			appliedAt := "Pending                 "
+			if m.AppliedAt != nil {
+				appliedAt = m.AppliedAt.Local().Format(time.ANSIC)
+			}
+			fmt.Printf("    %s -- %d_%s\n", appliedAt, m.Version, m.Name)
+		}
+		return nil
+	case "reset":
+		return mongomigrate.Reset(ctx, db)
+	default:
+		return fmt.Errorf("未知命令: %s", command)
+	}
+}
@@ -0,0 +1,65 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+
+	"app/internal/initialization"
+	"app/pkg/rabbitmq"
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <queue>",
+	Short: "将死信队列 <queue>.dlq 中的消息移回业务队列",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		connect(cmd)
+		defer rabbitmq.Close()
+
+		moved, err := rabbitmq.ReplayDeadLetters(context.Background(), args[0], limit)
+		if err != nil {
+			log.Fatalf("重放死信失败 (已移动 %d 条): %v", moved, err)
+		}
+		fmt.Printf("✅ 已将 %d 条消息从 %s.dlq 移回 %s\n", moved, args[0], args[0])
+	},
+}
+
+var dlqCmd = &cobra.Command{
+	Use:   "dlq",
+	Short: "死信队列管理",
+}
+
+var cmd = &cobra.Command{
+	Use:   "rabbitmq",
+	Short: "RabbitMQ 管理",
+}
+
+// connect 加载配置并连接 RabbitMQ, 失败时退出
+func connect(cmd *cobra.Command) {
+	cfg, err := cmd.Flags().GetString("config")
+	if err != nil {
+		cfg = "./config.yaml"
+	}
+	initialization.LoadConfig(cfg)
+
+	if !initialization.RabbitmqConfig().Enabled() {
+		log.Fatalf("RabbitMQ 配置为空, 请检查 MQ_* 配置项")
+	}
+	if err := initialization.InitRabbitmq(); err != nil {
+		rabbitmq.Close()
+		log.Fatalf("连接 RabbitMQ 失败: %v", err)
+	}
+}
+
+func Register(rootCmd *cobra.Command) error {
+	replayCmd.Flags().Int("limit", 0, "最多移动的消息条数, 0 表示全部")
+	dlqCmd.AddCommand(replayCmd)
+	cmd.AddCommand(dlqCmd)
+	rootCmd.AddCommand(cmd)
+	return nil
+}
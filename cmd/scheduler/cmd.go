@@ -0,0 +1,146 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"app/internal/initialization"
+	"app/internal/lifecycle"
+	"app/pkg/rabbitmq"
+	"app/pkg/scheduler"
+)
+
+var cmd = &cobra.Command{
+	Use:   "scheduler",
+	Short: "只运行定时任务 (不启动 HTTP 服务)",
+	Run: func(cmd *cobra.Command, args []string) {
+		config := loadConfig(cmd)
+
+		fmt.Println("\n正在初始化...")
+
+		lc := lifecycle.New(time.Duration(config.ShutdownTimeout) * time.Second)
+
+		// 可选初始化数据库
+		if err := initialization.InitDatabaseConnection(); err != nil {
+			fmt.Printf("⚠️  数据库: %v\n", err)
+		}
+		lc.Append("数据库", initialization.CloseDatabaseConnection)
+
+		// 可选初始化 RabbitMQ (任务中可能需要发送消息)
+		if err := initialization.InitRabbitmq(); err != nil {
+			fmt.Printf("⚠️  RabbitMQ: %v\n", err)
+		}
+		lc.Append("RabbitMQ", rabbitmq.Shutdown)
+
+		// 发布事务发件箱中的消息
+		if relay := initialization.InitOutboxRelay(); relay != nil {
+			lc.Append("Outbox Relay", relay.Shutdown)
+		}
+
+		if err := initialization.InitScheduler(); err != nil {
+			log.Fatalf("初始化调度器失败: %v", err)
+		}
+		scheduler.Start()
+		lc.Append("Scheduler", scheduler.Shutdown)
+
+		fmt.Println("✅ 初始化完成")
+
+		if err := lc.Wait(); err != nil {
+			log.Fatalf("scheduler exited with error: %v", err)
+		}
+	},
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出所有已注册的定时任务",
+	Run: func(cmd *cobra.Command, args []string) {
+		config := loadConfig(cmd)
+
+		opts := scheduler.Options{
+			Timezone: config.SchedulerTZ,
+			Seconds:  config.SchedulerSeconds,
+		}
+		if err := scheduler.Init(opts); err != nil {
+			log.Fatalf("初始化调度器失败: %v", err)
+		}
+
+		jobs := scheduler.Jobs()
+		if len(jobs) == 0 {
+			fmt.Println("⚠️  没有注册任何定时任务")
+			return
+		}
+
+		now := time.Now()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tSPEC\tNEXT\tDESCRIPTION")
+		for _, job := range jobs {
+			var next string
+			if t, err := scheduler.NextRun(job, now); err != nil {
+				next = err.Error()
+			} else {
+				next = t.Format(time.DateTime)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", job.Name, job.Spec, next, job.Description)
+		}
+		w.Flush()
+	},
+}
+
+var runCmd = &cobra.Command{
+	Use:   "run <name>",
+	Short: "立即执行一次指定的定时任务",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config := loadConfig(cmd)
+
+		lc := lifecycle.New(time.Duration(config.ShutdownTimeout) * time.Second)
+		defer lc.Shutdown()
+
+		// 可选初始化数据库
+		if err := initialization.InitDatabaseConnection(); err != nil {
+			fmt.Printf("⚠️  数据库: %v\n", err)
+		}
+		lc.Append("数据库", initialization.CloseDatabaseConnection)
+
+		// 可选初始化 RabbitMQ
+		if err := initialization.InitRabbitmq(); err != nil {
+			fmt.Printf("⚠️  RabbitMQ: %v\n", err)
+		}
+		lc.Append("RabbitMQ", rabbitmq.Shutdown)
+
+		// 初始化分布式锁和执行记录存储, 手动执行同样记录到执行历史
+		if err := initialization.InitSchedulerBackend(); err != nil {
+			lc.Shutdown()
+			log.Fatalf("初始化调度器失败: %v", err)
+		}
+
+		start := time.Now()
+		if err := scheduler.RunJob(args[0]); err != nil {
+			lc.Shutdown()
+			log.Fatalf("执行任务失败: %v", err)
+		}
+		fmt.Printf("✅ 任务 %s 执行完成 (耗时: %s)\n", args[0], time.Since(start))
+	},
+}
+
+// loadConfig 读取 --config 参数并加载配置
+func loadConfig(cmd *cobra.Command) initialization.Config {
+	cfg, err := cmd.Flags().GetString("config")
+	if err != nil {
+		cfg = "./config.yaml"
+	}
+	return initialization.LoadConfig(cfg)
+}
+
+func Register(rootCmd *cobra.Command) error {
+	cmd.AddCommand(listCmd)
+	cmd.AddCommand(runCmd)
+	rootCmd.AddCommand(cmd)
+	return nil
+}
@@ -4,13 +4,17 @@ import (
 	"embed"
 	"fmt"
 	"log"
+	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/cobra"
 
 	"app/internal/handlers"
 	"app/internal/initialization"
+	"app/internal/lifecycle"
 	"app/pkg/rabbitmq"
+	"app/pkg/scheduler"
 )
 
 var (
@@ -32,16 +36,41 @@ var cmd = &cobra.Command{
 
 		fmt.Println("\n正在初始化...")
 
+		// 按初始化顺序注册关闭钩子, 退出时逆序关闭
+		lc := lifecycle.New(time.Duration(config.ShutdownTimeout) * time.Second)
+
 		// 可选初始化数据库
 		if err := initialization.InitDatabaseConnection(); err != nil {
 			fmt.Printf("⚠️  数据库: %v\n", err)
 		}
+		lc.Append("数据库", initialization.CloseDatabaseConnection)
 
 		// 可选初始化 RabbitMQ
-		if err := rabbitmq.NewRabbitmq(initialization.AppConfig.MqHost, initialization.AppConfig.MqPort); err != nil {
+		if err := initialization.InitRabbitmq(); err != nil {
 			fmt.Printf("⚠️  RabbitMQ: %v\n", err)
 		}
-		rabbitmq.ListenQueue()
+		if config.ConsumerEnable {
+			rabbitmq.ListenQueue()
+		}
+		lc.Append("RabbitMQ", rabbitmq.Shutdown)
+
+		// 发布事务发件箱中的消息
+		if relay := initialization.InitOutboxRelay(); relay != nil {
+			lc.Append("Outbox Relay", relay.Shutdown)
+		}
+
+		// 可选启动定时任务
+		if config.SchedulerEnable {
+			if err := initialization.InitScheduler(); err != nil {
+				lc.Shutdown()
+				log.Fatalf("初始化调度器失败: %v", err)
+			}
+			scheduler.Start()
+			lc.Append("Scheduler", scheduler.Shutdown)
+		} else if err := initialization.InitSchedulerBackend(); err != nil {
+			// 管理接口仍需读取执行历史和手动触发任务
+			fmt.Printf("⚠️  Scheduler: %v\n", err)
+		}
 
 		fmt.Println("✅ 初始化完成")
 
@@ -63,33 +92,47 @@ var cmd = &cobra.Command{
 
 		// 基础路由
 		r.GET("/api/health", handlers.Health)
+		r.GET("/api/ready", handlers.Ready)
 		r.GET("/api/hello", handlers.Hello)
 		r.POST("/api/echo", handlers.Echo)
 
+		// 管理接口, 未配置 ADMIN_TOKEN 时不注册
+		if config.AdminToken == "" {
+			fmt.Println("⏭️  ADMIN_TOKEN 未配置, 管理接口 /api/admin 未启用")
+		} else {
+			admin := r.Group("/api/admin", handlers.AdminAuth(config.AdminToken))
+			admin.GET("/jobs", handlers.ListJobs)
+			admin.GET("/jobs/:name/runs", handlers.ListJobRuns)
+			admin.POST("/jobs/:name/run", handlers.TriggerJob)
+			admin.POST("/jobs/:name/pause", handlers.PauseJob)
+			admin.POST("/jobs/:name/resume", handlers.ResumeJob)
+		}
+
 		// 配置前端静态文件服务
 		SetWebRouter(r, buildFS, indexPage)
 
-		err = startHTTPServer(config, r)
-		if err != nil {
-			log.Fatalf("failed to start server: %v", err)
+		startHTTPServer(lc, config, r)
+
+		if err := lc.Wait(); err != nil {
+			log.Fatalf("server exited with error: %v", err)
 		}
 	},
 }
 
-func startHTTPServer(config initialization.Config, r *gin.Engine) (err error) {
-	addr := fmt.Sprintf("%s:%d", config.HttpHost, config.HttpPort)
+func startHTTPServer(lc *lifecycle.Manager, config initialization.Config, r *gin.Engine) {
+	srv := &http.Server{
+		Addr:    fmt.Sprintf("%s:%d", config.HttpHost, config.HttpPort),
+		Handler: r,
+	}
 
 	fmt.Println("\n========================================")
 	fmt.Printf("🚀 Server is running!\n\n")
 	fmt.Printf("➜ Local:   http://localhost:%d/\n", config.HttpPort)
 	fmt.Printf("➜ Network: http://127.0.0.1:%d/\n", config.HttpPort)
-	fmt.Println("========================================\n")
+	fmt.Println("========================================")
+	fmt.Println()
 
-	err = r.Run(addr)
-	if err != nil {
-		return fmt.Errorf("failed to start http server: %v", err)
-	}
-	return nil
+	lc.Serve("HTTP Server", srv)
 }
 
 func Register(rootCmd *cobra.Command, fs embed.FS, index []byte) error {
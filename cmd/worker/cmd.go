@@ -0,0 +1,82 @@
+package worker
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"app/internal/initialization"
+	"app/internal/lifecycle"
+	"app/pkg/rabbitmq"
+	"app/pkg/scheduler"
+)
+
+var cmd = &cobra.Command{
+	Use:   "worker",
+	Short: "只运行队列消费者 (不启动 HTTP 服务)",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := cmd.Flags().GetString("config")
+		if err != nil {
+			cfg = "./config.yaml"
+		}
+		withScheduler, _ := cmd.Flags().GetBool("scheduler")
+
+		config := initialization.LoadConfig(cfg)
+		if !initialization.RabbitmqConfig().Enabled() {
+			log.Fatalf("RabbitMQ 配置为空, 请检查 MQ_* 配置项")
+		}
+
+		fmt.Println("\n正在初始化...")
+
+		lc := lifecycle.New(time.Duration(config.ShutdownTimeout) * time.Second)
+
+		// 可选初始化数据库
+		if err := initialization.InitDatabaseConnection(); err != nil {
+			fmt.Printf("⚠️  数据库: %v\n", err)
+		}
+		lc.Append("数据库", initialization.CloseDatabaseConnection)
+
+		// 首次连接失败时后台重连, 连接成功后自动开始消费
+		if err := initialization.InitRabbitmq(); err != nil {
+			fmt.Printf("⚠️  RabbitMQ: %v\n", err)
+		}
+		n := rabbitmq.StartConsumers()
+		if n == 0 {
+			fmt.Println("⚠️  没有注册任何队列消费者")
+		}
+		lc.Append("RabbitMQ", rabbitmq.Shutdown)
+
+		// 发布事务发件箱中的消息
+		if relay := initialization.InitOutboxRelay(); relay != nil {
+			lc.Append("Outbox Relay", relay.Shutdown)
+		}
+
+		// 可选同时运行定时任务
+		if withScheduler {
+			if err := initialization.InitScheduler(); err != nil {
+				lc.Shutdown()
+				log.Fatalf("初始化调度器失败: %v", err)
+			}
+			scheduler.Start()
+			lc.Append("Scheduler", scheduler.Shutdown)
+		}
+
+		if config.WorkerHttpPort > 0 {
+			startHealthServer(lc, config.WorkerHttpPort)
+		}
+
+		fmt.Printf("✅ 初始化完成, 已注册 %d 个消费者\n", n)
+
+		if err := lc.Wait(); err != nil {
+			log.Fatalf("worker exited with error: %v", err)
+		}
+	},
+}
+
+func Register(rootCmd *cobra.Command) error {
+	cmd.Flags().Bool("scheduler", false, "同时运行定时任务")
+	rootCmd.AddCommand(cmd)
+	return nil
+}
@@ -0,0 +1,96 @@
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"app/internal/initialization"
+	"app/internal/lifecycle"
+	"app/pkg/rabbitmq"
+)
+
+// startHealthServer 启动健康检查和指标端口
+//
+//	GET /health   存活检查: RabbitMQ 和数据库连接状态及各队列消费统计, 依赖异常时 status 为 degraded, 始终返回 200
+//	GET /ready    就绪检查: 内容同 /health, RabbitMQ 或已配置的数据库未连接时返回 503
+//	GET /metrics  Prometheus 文本格式的消费统计
+//
+// 就绪判断与 server 的 /api/health、/api/ready 共用 initialization.CheckReadiness
+func startHealthServer(lc *lifecycle.Manager, port int) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", health)
+	mux.HandleFunc("/ready", ready)
+	mux.HandleFunc("/metrics", metrics)
+
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: mux,
+	}
+	fmt.Printf("➜ Health:  http://localhost:%d/health\n", port)
+	lc.Serve("Health Server", srv)
+}
+
+func health(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, healthData(initialization.CheckReadiness(r.Context())))
+}
+
+func ready(w http.ResponseWriter, r *http.Request) {
+	readiness := initialization.CheckReadiness(r.Context())
+	code := http.StatusOK
+	if !readiness.Ready {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, healthData(readiness))
+}
+
+// healthData 健康检查响应内容, 在连接状态之外附带各队列消费统计
+func healthData(r initialization.Readiness) map[string]any {
+	return map[string]any{
+		"status":    r.Status,
+		"rabbitmq":  r.RabbitMQ,
+		"databases": r.Databases,
+		"consumers": rabbitmq.Stats(),
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func metrics(w http.ResponseWriter, r *http.Request) {
+	stats := rabbitmq.Stats()
+	mq := rabbitmq.Health()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	connected := 0
+	if mq.Connected {
+		connected = 1
+	}
+	fmt.Fprintln(w, "# HELP rabbitmq_connected Whether the RabbitMQ connection is up.")
+	fmt.Fprintln(w, "# TYPE rabbitmq_connected gauge")
+	fmt.Fprintf(w, "rabbitmq_connected %d\n", connected)
+	fmt.Fprintln(w, "# HELP rabbitmq_reconnects_total Successful reconnects since start.")
+	fmt.Fprintln(w, "# TYPE rabbitmq_reconnects_total counter")
+	fmt.Fprintf(w, "rabbitmq_reconnects_total %d\n", mq.Reconnects)
+
+	series := []struct {
+		name, kind, help string
+		value            func(rabbitmq.ConsumerStats) any
+	}{
+		{"rabbitmq_messages_processed_total", "counter", "Messages handled successfully.", func(s rabbitmq.ConsumerStats) any { return s.Processed }},
+		{"rabbitmq_messages_failed_total", "counter", "Messages whose handler returned an error.", func(s rabbitmq.ConsumerStats) any { return s.Failed }},
+		{"rabbitmq_messages_dead_lettered_total", "counter", "Messages moved to the dead letter queue.", func(s rabbitmq.ConsumerStats) any { return s.DeadLettered }},
+		{"rabbitmq_messages_in_flight", "gauge", "Messages currently being handled.", func(s rabbitmq.ConsumerStats) any { return s.InFlight }},
+	}
+	for _, c := range series {
+		fmt.Fprintf(w, "# HELP %s %s\n", c.name, c.help)
+		fmt.Fprintf(w, "# TYPE %s %s\n", c.name, c.kind)
+		for _, s := range stats {
+			fmt.Fprintf(w, "%s{queue=%q} %v\n", c.name, s.Queue, c.value(s))
+		}
+	}
+}
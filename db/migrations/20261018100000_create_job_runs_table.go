@@ -0,0 +1,53 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateJobRunsTable, downCreateJobRunsTable)
+}
+
+// JobRun 定时任务执行记录表模型
+type JobRun struct {
+	ID         uint      `gorm:"primaryKey"`
+	JobName    string    `gorm:"type:varchar(100);index;not null"`
+	Trigger    string    `gorm:"type:varchar(20);not null"`
+	Status     string    `gorm:"type:varchar(20);index;not null"`
+	Error      string    `gorm:"type:text"`
+	Host       string    `gorm:"type:varchar(100)"`
+	StartedAt  time.Time `gorm:"index;not null"`
+	FinishedAt time.Time `gorm:"not null"`
+	DurationMs int64     `gorm:"not null"`
+}
+
+func upCreateJobRunsTable(ctx context.Context, tx *sql.Tx) error {
+	db, err := openGormDB(tx)
+	if err != nil {
+		return err
+	}
+
+	if err := db.AutoMigrate(&JobRun{}); err != nil {
+		return fmt.Errorf("failed to migrate: %w", err)
+	}
+
+	return nil
+}
+
+func downCreateJobRunsTable(ctx context.Context, tx *sql.Tx) error {
+	db, err := openGormDB(tx)
+	if err != nil {
+		return err
+	}
+
+	if err := db.Migrator().DropTable(&JobRun{}); err != nil {
+		return fmt.Errorf("failed to drop table: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateOutboxMessagesTable, downCreateOutboxMessagesTable)
+}
+
+// OutboxMessage 事务发件箱表模型
+type OutboxMessage struct {
+	ID            uint64 `gorm:"primaryKey"`
+	MessageID     string `gorm:"type:varchar(64);uniqueIndex;not null"`
+	Exchange      string `gorm:"type:varchar(255);not null"`
+	RoutingKey    string `gorm:"type:varchar(255);not null"`
+	ContentType   string `gorm:"type:varchar(100)"`
+	Headers       string `gorm:"type:text"`
+	CorrelationID string `gorm:"type:varchar(255)"`
+	Priority      uint8
+	TTLMs         int64
+	Transient     bool
+	Body          []byte     `gorm:"not null"`
+	Status        string     `gorm:"type:varchar(20);not null;index:idx_outbox_pending,priority:1"`
+	Attempts      int        `gorm:"not null"`
+	LastError     string     `gorm:"type:text"`
+	NextAttemptAt time.Time  `gorm:"not null;index:idx_outbox_pending,priority:2"`
+	CreatedAt     time.Time  `gorm:"not null"`
+	SentAt        *time.Time `gorm:"index"`
+}
+
+func upCreateOutboxMessagesTable(ctx context.Context, tx *sql.Tx) error {
+	db, err := openGormDB(tx)
+	if err != nil {
+		return err
+	}
+
+	if err := db.AutoMigrate(&OutboxMessage{}); err != nil {
+		return fmt.Errorf("failed to migrate: %w", err)
+	}
+
+	return nil
+}
+
+func downCreateOutboxMessagesTable(ctx context.Context, tx *sql.Tx) error {
+	db, err := openGormDB(tx)
+	if err != nil {
+		return err
+	}
+
+	if err := db.Migrator().DropTable(&OutboxMessage{}); err != nil {
+		return fmt.Errorf("failed to drop table: %w", err)
+	}
+
+	return nil
+}
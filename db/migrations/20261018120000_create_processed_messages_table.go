@@ -0,0 +1,47 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateProcessedMessagesTable, downCreateProcessedMessagesTable)
+}
+
+// ProcessedMessage 消费去重记录表模型 (MessageKey 为 队列名称:消息 ID 的 SHA-256)
+type ProcessedMessage struct {
+	MessageKey  string    `gorm:"type:char(64);primaryKey"`
+	ExpiresAt   time.Time `gorm:"index;not null"`
+	ProcessedAt time.Time `gorm:"not null"`
+}
+
+func upCreateProcessedMessagesTable(ctx context.Context, tx *sql.Tx) error {
+	db, err := openGormDB(tx)
+	if err != nil {
+		return err
+	}
+
+	if err := db.AutoMigrate(&ProcessedMessage{}); err != nil {
+		return fmt.Errorf("failed to migrate: %w", err)
+	}
+
+	return nil
+}
+
+func downCreateProcessedMessagesTable(ctx context.Context, tx *sql.Tx) error {
+	db, err := openGormDB(tx)
+	if err != nil {
+		return err
+	}
+
+	if err := db.Migrator().DropTable(&ProcessedMessage{}); err != nil {
+		return fmt.Errorf("failed to drop table: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateJobPausesTable, downCreateJobPausesTable)
+}
+
+// JobPause 定时任务暂停状态表模型
+type JobPause struct {
+	JobName  string    `gorm:"type:varchar(100);primaryKey"`
+	PausedAt time.Time `gorm:"not null"`
+}
+
+func upCreateJobPausesTable(ctx context.Context, tx *sql.Tx) error {
+	db, err := openGormDB(tx)
+	if err != nil {
+		return err
+	}
+
+	if err := db.AutoMigrate(&JobPause{}); err != nil {
+		return fmt.Errorf("failed to migrate: %w", err)
+	}
+
+	return nil
+}
+
+func downCreateJobPausesTable(ctx context.Context, tx *sql.Tx) error {
+	db, err := openGormDB(tx)
+	if err != nil {
+		return err
+	}
+
+	if err := db.Migrator().DropTable(&JobPause{}); err != nil {
+		return fmt.Errorf("failed to drop table: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"app/internal/initialization"
+)
+
+// openGormDB 根据数据库类型 (DB_TYPE 配置项) 打开 GORM 连接
+func openGormDB(tx *sql.Tx) (*gorm.DB, error) {
+	dbType := initialization.AppConfig.DbType
+	if dbType == "" {
+		dbType = "mysql" // 默认使用 MySQL
+	}
+
+	var dialector gorm.Dialector
+	switch dbType {
+	case "postgres", "postgresql":
+		dialector = postgres.New(postgres.Config{
+			Conn: tx,
+		})
+	case "mysql":
+		dialector = mysql.New(mysql.Config{
+			Conn: tx,
+		})
+	case "sqlite":
+		dialector = sqlite.Dialector{
+			Conn: tx,
+		}
+	default:
+		return nil, fmt.Errorf("不支持的数据库类型: %s", dbType)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gorm instance: %w", err)
+	}
+
+	return db, nil
+}
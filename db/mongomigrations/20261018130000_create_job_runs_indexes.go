@@ -0,0 +1,36 @@
+package mongomigrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"app/pkg/mongomigrate"
+)
+
+func init() {
+	mongomigrate.Add(upCreateJobRunsIndexes, downCreateJobRunsIndexes)
+}
+
+// job_runs 集合的索引, 与 SQL 迁移中 job_runs 表的索引对应
+func upCreateJobRunsIndexes(ctx context.Context, db *mongo.Database) error {
+	return mongomigrate.EnsureIndexes(ctx, db, "job_runs",
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "job_name", Value: 1}, {Key: "started_at", Value: -1}},
+			Options: options.Index().SetName("idx_job_runs_job_name_started_at"),
+		},
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "started_at", Value: -1}},
+			Options: options.Index().SetName("idx_job_runs_started_at"),
+		},
+	)
+}
+
+func downCreateJobRunsIndexes(ctx context.Context, db *mongo.Database) error {
+	return mongomigrate.DropIndexes(ctx, db, "job_runs",
+		"idx_job_runs_job_name_started_at",
+		"idx_job_runs_started_at",
+	)
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAuth 管理接口鉴权中间件, token 为空时拒绝所有请求
+// 支持 Authorization: Bearer <token> 或 X-Admin-Token: <token>
+func AdminAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			abortWithStatus(c, http.StatusForbidden, "管理接口未启用")
+			return
+		}
+
+		provided := c.GetHeader("X-Admin-Token")
+		if auth := c.GetHeader("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			provided = strings.TrimPrefix(auth, "Bearer ")
+		}
+
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			abortWithStatus(c, http.StatusUnauthorized, "未授权")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// abortWithStatus 以 HTTP 状态码拒绝请求, 响应体与其他接口格式一致
+func abortWithStatus(c *gin.Context, status int, errMsg string) {
+	c.AbortWithStatusJSON(status, gin.H{
+		"err_code": status,
+		"err_msg":  errMsg,
+		"data":     nil,
+	})
+}
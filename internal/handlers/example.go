@@ -1,14 +1,44 @@
 package handlers
 
-import "github.com/gin-gonic/gin"
+import (
+	"net/http"
 
-// Health 健康检查
+	"github.com/gin-gonic/gin"
+
+	"app/internal/initialization"
+)
+
+// Health 存活检查
+// 依赖组件异常时 status 为 degraded, HTTP 状态码保持 200, 避免因外部依赖故障导致实例被重启
 func Health(c *gin.Context) {
 	resp := NewResp(c)
-	resp.successWithData(gin.H{
-		"status": "ok",
-		"service": "app",
-	}, nil)
+	resp.successWithData(healthData(initialization.CheckReadiness(c.Request.Context())), nil)
+}
+
+// Ready 就绪检查
+// 已配置的 RabbitMQ 或数据存储未连接时返回 HTTP 503, 供负载均衡和编排系统摘除流量
+func Ready(c *gin.Context) {
+	r := initialization.CheckReadiness(c.Request.Context())
+	if !r.Ready {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"err_code": http.StatusServiceUnavailable,
+			"err_msg":  "服务未就绪",
+			"data":     healthData(r),
+		})
+		return
+	}
+	resp := NewResp(c)
+	resp.successWithData(healthData(r), nil)
+}
+
+// healthData 健康检查响应内容
+func healthData(r initialization.Readiness) gin.H {
+	return gin.H{
+		"status":    r.Status,
+		"service":   "app",
+		"rabbitmq":  r.RabbitMQ,
+		"databases": r.Databases,
+	}
 }
 
 // Hello GET 示例
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"app/pkg/scheduler"
+)
+
+// ListJobs 定时任务列表 (含下一次/上一次执行时间)
+func ListJobs(c *gin.Context) {
+	resp := NewResp(c)
+	resp.successWithData(scheduler.Statuses(c.Request.Context()), nil)
+}
+
+// ListJobRuns 定时任务最近的执行记录
+func ListJobRuns(c *gin.Context) {
+	resp := NewResp(c)
+	name := c.Param("name")
+
+	if _, ok := scheduler.FindJob(name); !ok {
+		resp.failWithErrCode("任务不存在", 404)
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 200 {
+		resp.fail("limit 取值范围为 1-200")
+		return
+	}
+
+	runs, err := scheduler.RecentRuns(c.Request.Context(), name, limit)
+	if err != nil {
+		resp.failWithErrCode("查询执行记录失败: "+err.Error(), 500)
+		return
+	}
+	resp.successWithData(runs, nil)
+}
+
+// TriggerJob 立即执行一次定时任务 (后台执行, 结果见执行记录)
+func TriggerJob(c *gin.Context) {
+	resp := NewResp(c)
+	name := c.Param("name")
+
+	if err := scheduler.Trigger(name); err != nil {
+		failWithJobError(resp, err)
+		return
+	}
+	resp.successWithData(gin.H{
+		"name":      name,
+		"triggered": true,
+	}, nil)
+}
+
+// PauseJob 暂停定时任务
+func PauseJob(c *gin.Context) {
+	resp := NewResp(c)
+	name := c.Param("name")
+
+	if err := scheduler.Pause(c.Request.Context(), name); err != nil {
+		failWithJobError(resp, err)
+		return
+	}
+	jobStatus(resp, name)
+}
+
+// ResumeJob 恢复定时任务
+func ResumeJob(c *gin.Context) {
+	resp := NewResp(c)
+	name := c.Param("name")
+
+	if err := scheduler.Resume(c.Request.Context(), name); err != nil {
+		failWithJobError(resp, err)
+		return
+	}
+	jobStatus(resp, name)
+}
+
+// jobStatus 返回单个任务的运行状态
+func jobStatus(resp *Resp, name string) {
+	status, err := scheduler.Status(resp.Context.Request.Context(), name)
+	if err != nil {
+		failWithJobError(resp, err)
+		return
+	}
+	resp.successWithData(status, nil)
+}
+
+// failWithJobError 根据任务错误类型返回对应错误码
+func failWithJobError(resp *Resp, err error) {
+	if errors.Is(err, scheduler.ErrJobNotFound) {
+		resp.failWithErrCode(err.Error(), 404)
+		return
+	}
+	if errors.Is(err, scheduler.ErrJobNotScheduled) {
+		resp.failWithErrCode(err.Error(), 409)
+		return
+	}
+	resp.fail(err.Error())
+}
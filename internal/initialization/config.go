@@ -19,8 +19,90 @@ type Config struct {
 	DbDatabase     string   `json:"dbDatabase"`
 	DbUsername     string   `json:"dbUsername"`
 	DbPassword     string   `json:"dbPassword"`
-	MqHost         string   `json:"mqHost"`
-	MqPort         int      `json:"mqPort"`
+
+	DbReplicas []string `json:"dbReplicas"` // 只读副本地址 (host 或 host:port), 与主库使用相同的账号和数据库名
+
+	DbMaxOpenConns    int `json:"dbMaxOpenConns"`    // 最大打开连接数, 0 表示不限制
+	DbMaxIdleConns    int `json:"dbMaxIdleConns"`    // 最大空闲连接数
+	DbConnMaxLifetime int `json:"dbConnMaxLifetime"` // 连接最长存活时间(秒), 需小于数据库的 wait_timeout, 0 表示不限制
+	DbConnMaxIdleTime int `json:"dbConnMaxIdleTime"` // 连接最长空闲时间(秒), 0 表示不限制
+	DbConnectRetries  int `json:"dbConnectRetries"`  // 启动时连接失败的重试次数 (指数退避, 最长间隔 30s)
+
+	Databases map[string]DatabaseConfig `json:"databases"` // 其他命名数据库连接 (databases 配置段), 通过 DB(name) / Mongo(name) 获取
+
+	MqTransport string `json:"mqTransport"` // 传输层: amqp / memory / none, 为空时配置了 MQ_HOST 使用 amqp, 否则不启用
+	MqHost      string `json:"mqHost"`
+	MqPort      int    `json:"mqPort"`
+
+	MqUsername       string `json:"mqUsername"`
+	MqPassword       string `json:"-"`
+	MqVhost          string `json:"mqVhost"`
+	MqURL            string `json:"-"`                // 完整连接地址, 设置后忽略 MQ_HOST 等配置 (可能包含密码, 不打印)
+	MqHeartbeat      int    `json:"mqHeartbeat"`      // 心跳间隔(秒)
+	MqConnectionName string `json:"mqConnectionName"` // 管理界面中显示的连接名称
+	MqTLS            bool   `json:"mqTLS"`            // 使用 amqps 连接
+	MqCACert         string `json:"mqCACert"`
+	MqClientCert     string `json:"mqClientCert"`
+	MqClientKey      string `json:"mqClientKey"`
+	MqTLSSkipVerify  bool   `json:"mqTLSSkipVerify"`
+
+	ShutdownTimeout  int    `json:"shutdownTimeout"`  // 优雅关闭超时时间(秒)
+	SchedulerEnable  bool   `json:"schedulerEnable"`  // server 进程是否同时运行定时任务
+	SchedulerTZ      string `json:"schedulerTZ"`      // 定时任务默认时区
+	SchedulerSeconds bool   `json:"schedulerSeconds"` // 是否启用秒级 Cron 表达式
+
+	ConsumerEnable bool `json:"consumerEnable"` // server 进程是否同时消费队列
+	WorkerHttpPort int  `json:"workerHttpPort"` // worker 健康检查/指标端口, 0 表示不启用
+
+	OutboxRelayEnable bool `json:"outboxRelayEnable"` // 是否运行发件箱 Relay
+
+	AdminToken string `json:"-"` // 管理接口访问令牌
+}
+
+// DatabaseConfig 数据库连接配置
+// DB_* 配置项为默认连接 (名称 default), databases 配置段中的每一项为一个命名连接
+type DatabaseConfig struct {
+	Type     string   `json:"type" mapstructure:"type"` // mysql / postgres / mongodb / sqlite
+	Host     string   `json:"host" mapstructure:"host"`
+	Port     int      `json:"port" mapstructure:"port"`         // 默认按类型: 3306 / 5432 / 27017
+	Database string   `json:"database" mapstructure:"database"` // SQLite 为文件路径或 :memory:
+	Username string   `json:"username" mapstructure:"username"`
+	Password string   `json:"-" mapstructure:"password"`
+	Replicas []string `json:"replicas,omitempty" mapstructure:"replicas"` // 只读副本 (host 或 host:port)
+
+	// 连接池, 未设置时使用 DB_MAX_OPEN_CONNS 等默认连接的配置
+	MaxOpenConns    int `json:"maxOpenConns" mapstructure:"max_open_conns"`
+	MaxIdleConns    int `json:"maxIdleConns" mapstructure:"max_idle_conns"`
+	ConnMaxLifetime int `json:"connMaxLifetime" mapstructure:"conn_max_lifetime"`  // 秒
+	ConnMaxIdleTime int `json:"connMaxIdleTime" mapstructure:"conn_max_idle_time"` // 秒
+}
+
+// Configured 是否配置了数据库, SQLite 只需要 Database
+func (c DatabaseConfig) Configured() bool {
+	if c.Type == "sqlite" {
+		return c.Database != ""
+	}
+	return c.Host != "" && c.Database != ""
+}
+
+// DefaultDatabase 默认连接的名称, 即 DB_* 配置项对应的连接
+const DefaultDatabase = "default"
+
+// DefaultDatabaseConfig 由 DB_* 配置项构造的默认连接配置
+func (c Config) DefaultDatabaseConfig() DatabaseConfig {
+	return DatabaseConfig{
+		Type:            c.DbType,
+		Host:            c.DbHost,
+		Port:            c.DbPort,
+		Database:        c.DbDatabase,
+		Username:        c.DbUsername,
+		Password:        c.DbPassword,
+		Replicas:        c.DbReplicas,
+		MaxOpenConns:    c.DbMaxOpenConns,
+		MaxIdleConns:    c.DbMaxIdleConns,
+		ConnMaxLifetime: c.DbConnMaxLifetime,
+		ConnMaxIdleTime: c.DbConnMaxIdleTime,
+	}
 }
 
 var AppConfig Config
@@ -30,6 +112,13 @@ func LoadConfig(cfg string) Config {
 	viper.ReadInConfig()
 	viper.AutomaticEnv()
 
+	// amqps 默认端口为 5671
+	mqTLS := getViperBoolValue("MQ_TLS", false)
+	mqDefaultPort := 5672
+	if mqTLS {
+		mqDefaultPort = 5671
+	}
+
 	AppConfig = Config{
 		HttpHost:       getViperStringValue("HTTP_Host", "0.0.0.0"),
 		HttpPort:       getViperIntValue("HTTP_PORT", 3000),
@@ -40,15 +129,99 @@ func LoadConfig(cfg string) Config {
 		DbDatabase:     viper.GetString("DB_DATABASE"), // 不使用默认值，保持空字符串
 		DbUsername:     getViperStringValue("DB_USERNAME", "root"),
 		DbPassword:     getViperStringValue("DB_PASSWORD", "root"),
-		MqHost:         viper.GetString("MQ_HOST"), // 不使用默认值，保持空字符串
-		MqPort:         getViperIntValue("MQ_PORT", 5672),
+
+		DbReplicas: getViperStringArray("DB_REPLICAS", nil),
+
+		DbMaxOpenConns:    getViperIntValue("DB_MAX_OPEN_CONNS", 50),
+		DbMaxIdleConns:    getViperIntValue("DB_MAX_IDLE_CONNS", 10),
+		DbConnMaxLifetime: getViperIntValue("DB_CONN_MAX_LIFETIME", 1800),
+		DbConnMaxIdleTime: getViperIntValue("DB_CONN_MAX_IDLE_TIME", 300),
+		DbConnectRetries:  getViperIntValue("DB_CONNECT_RETRIES", 5),
+
+		MqTransport: viper.GetString("MQ_TRANSPORT"),
+		MqHost:      viper.GetString("MQ_HOST"), // 不使用默认值，保持空字符串
+		MqPort:      getViperIntValue("MQ_PORT", mqDefaultPort),
+
+		MqUsername:       getViperStringValue("MQ_USERNAME", "guest"),
+		MqPassword:       getViperStringValue("MQ_PASSWORD", "guest"),
+		MqVhost:          getViperStringValue("MQ_VHOST", "/"),
+		MqURL:            viper.GetString("MQ_URL"),
+		MqHeartbeat:      getViperIntValue("MQ_HEARTBEAT", 10),
+		MqConnectionName: viper.GetString("MQ_CONNECTION_NAME"),
+		MqTLS:            mqTLS,
+		MqCACert:         viper.GetString("MQ_TLS_CA_CERT"),
+		MqClientCert:     viper.GetString("MQ_TLS_CLIENT_CERT"),
+		MqClientKey:      viper.GetString("MQ_TLS_CLIENT_KEY"),
+		MqTLSSkipVerify:  getViperBoolValue("MQ_TLS_SKIP_VERIFY", false),
+
+		ShutdownTimeout:  getViperIntValue("SHUTDOWN_TIMEOUT", 15),
+		SchedulerEnable:  getViperBoolValue("SCHEDULER_ENABLE", false),
+		SchedulerTZ:      getViperStringValue("SCHEDULER_TZ", "Asia/Shanghai"),
+		SchedulerSeconds: getViperBoolValue("SCHEDULER_SECONDS", false),
+
+		ConsumerEnable: getViperBoolValue("CONSUMER_ENABLE", true),
+		WorkerHttpPort: getViperIntValue("WORKER_HTTP_PORT", 3001),
+
+		OutboxRelayEnable: getViperBoolValue("OUTBOX_RELAY_ENABLE", true),
+
+		AdminToken: viper.GetString("ADMIN_TOKEN"),
 	}
+	AppConfig.Databases = loadDatabases(AppConfig)
+
 	configJSON, _ := json.MarshalIndent(AppConfig, "", "  ")
 	fmt.Printf("读取到的配置信息:\n%s\n", string(configJSON))
 
 	return AppConfig
 }
 
+// loadDatabases 读取 databases 配置段, 未设置的端口和连接池参数使用默认值
+func loadDatabases(c Config) map[string]DatabaseConfig {
+	var databases map[string]DatabaseConfig
+	if err := viper.UnmarshalKey("databases", &databases); err != nil {
+		fmt.Printf("Invalid value for databases, ignored: %v\n", err)
+		return nil
+	}
+
+	for name, db := range databases {
+		if name == DefaultDatabase {
+			fmt.Printf("databases.%s is reserved for DB_* settings, ignored\n", name)
+			delete(databases, name)
+			continue
+		}
+
+		key := "databases." + name + "."
+		if db.Port == 0 {
+			db.Port = defaultDatabasePort(db.Type)
+		}
+		if !viper.IsSet(key + "max_open_conns") {
+			db.MaxOpenConns = c.DbMaxOpenConns
+		}
+		if !viper.IsSet(key + "max_idle_conns") {
+			db.MaxIdleConns = c.DbMaxIdleConns
+		}
+		if !viper.IsSet(key + "conn_max_lifetime") {
+			db.ConnMaxLifetime = c.DbConnMaxLifetime
+		}
+		if !viper.IsSet(key + "conn_max_idle_time") {
+			db.ConnMaxIdleTime = c.DbConnMaxIdleTime
+		}
+		databases[name] = db
+	}
+	return databases
+}
+
+// defaultDatabasePort 数据库类型的默认端口
+func defaultDatabasePort(dbType string) int {
+	switch dbType {
+	case "postgres", "postgresql":
+		return 5432
+	case "mongodb", "mongo":
+		return 27017
+	default:
+		return 3306
+	}
+}
+
 func getViperStringValue(key string, defaultValue string) string {
 	value := viper.GetString(key)
 	if value == "" {
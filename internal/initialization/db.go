@@ -2,9 +2,16 @@ package initialization
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/glebarez/sqlite"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"gorm.io/driver/mysql"
@@ -12,106 +19,354 @@ import (
 	"gorm.io/gorm"
 )
 
+// 默认连接 (DB_* 配置项), 与 DB(DefaultDatabase) / Mongo(DefaultDatabase) 相同
 var Db *gorm.DB
 var MongoClient *mongo.Client
 var MongoDB *mongo.Database
 
+// 启动时连接重试的退避时间
+const (
+	minConnectRetryDelay = time.Second
+	maxConnectRetryDelay = 30 * time.Second
+)
+
+// database 已初始化的数据库连接
+type database struct {
+	config      DatabaseConfig
+	gorm        *gorm.DB
+	mongoClient *mongo.Client
+	mongo       *mongo.Database
+	replicas    *replicaSet
+}
+
+// databases 已连接成功的数据库, 按名称索引
+var (
+	databasesMu sync.RWMutex
+	databases   = make(map[string]*database)
+)
+
+// databaseConfigs 所有已配置的数据库连接 (含默认连接), 按名称索引
+func databaseConfigs() map[string]DatabaseConfig {
+	configs := make(map[string]DatabaseConfig, len(AppConfig.Databases)+1)
+	if cfg := AppConfig.DefaultDatabaseConfig(); cfg.Configured() {
+		configs[DefaultDatabase] = cfg
+	}
+	for name, cfg := range AppConfig.Databases {
+		if cfg.Configured() {
+			configs[name] = cfg
+		}
+	}
+	return configs
+}
+
+// DB 返回命名的 SQL 数据库连接, DB(DefaultDatabase) 即 Db
+// 已配置但连接失败或不是 SQL 数据库时返回 nil; 名称未在 databases 中配置时 panic, 便于发现拼写错误
+func DB(name string) *gorm.DB {
+	if db := lookupDatabase(name); db != nil {
+		return db.gorm
+	}
+	return nil
+}
+
+// Mongo 返回命名的 MongoDB 数据库, Mongo(DefaultDatabase) 即 MongoDB
+// 已配置但连接失败或不是 MongoDB 时返回 nil; 名称未在 databases 中配置时 panic
+func Mongo(name string) *mongo.Database {
+	if db := lookupDatabase(name); db != nil {
+		return db.mongo
+	}
+	return nil
+}
+
+func lookupDatabase(name string) *database {
+	if _, ok := AppConfig.Databases[name]; !ok && name != DefaultDatabase {
+		panic(fmt.Sprintf("initialization: 数据库 %s 未配置 (databases.%s)", name, name))
+	}
+
+	databasesMu.RLock()
+	defer databasesMu.RUnlock()
+	return databases[name]
+}
+
+// InitDatabaseConnection 初始化默认连接和 databases 中的命名连接
+// 某个连接失败时不影响其他连接, 返回所有失败连接的错误
 func InitDatabaseConnection() error {
-	// 检查数据库配置是否为空
-	if AppConfig.DbHost == "" || AppConfig.DbDatabase == "" {
+	configs := databaseConfigs()
+	if len(configs) == 0 {
 		fmt.Println("⏭️  数据库配置为空，跳过初始化")
 		return nil
 	}
 
-	fmt.Printf("正在初始化 %s 数据库连接...\n", AppConfig.DbType)
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(configs)) {
+		cfg := configs[name]
+		fmt.Printf("正在初始化 %s 数据库连接%s...\n", cfg.Type, databaseLabel(name))
+
+		var db *database
+		open, err := databaseOpener(cfg.Type)
+		if err == nil {
+			err = connectWithRetry(func() (err error) {
+				db, err = open(cfg)
+				return err
+			})
+		}
+		if err != nil {
+			if name != DefaultDatabase {
+				err = fmt.Errorf("%s: %w", name, err)
+			}
+			errs = append(errs, err)
+			continue
+		}
+
+		databasesMu.Lock()
+		databases[name] = db
+		databasesMu.Unlock()
+		if name == DefaultDatabase {
+			Db, MongoClient, MongoDB = db.gorm, db.mongoClient, db.mongo
+		}
+		fmt.Printf("✅ %s 数据库连接成功%s\n", databaseTypeName(cfg.Type), databaseLabel(name))
+	}
+	return errors.Join(errs...)
+}
+
+// databaseLabel 日志中的连接名称, 默认连接不显示
+func databaseLabel(name string) string {
+	if name == DefaultDatabase {
+		return ""
+	}
+	return " [" + name + "]"
+}
+
+func databaseTypeName(dbType string) string {
+	switch dbType {
+	case "mysql":
+		return "MySQL"
+	case "postgres", "postgresql":
+		return "PostgreSQL"
+	case "mongodb", "mongo":
+		return "MongoDB"
+	case "sqlite":
+		return "SQLite"
+	default:
+		return dbType
+	}
+}
 
-	switch AppConfig.DbType {
+// databaseOpener 按数据库类型返回打开连接的函数
+func databaseOpener(dbType string) (func(DatabaseConfig) (*database, error), error) {
+	switch dbType {
 	case "mysql":
-		return initMySQL()
+		return openMySQL, nil
 	case "postgres", "postgresql":
-		return initPostgreSQL()
+		return openPostgreSQL, nil
 	case "mongodb", "mongo":
-		return initMongoDB()
+		return openMongoDB, nil
+	case "sqlite":
+		return openSQLite, nil
 	default:
-		return fmt.Errorf("不支持的数据库类型: %s (支持: mysql, postgres, mongodb)", AppConfig.DbType)
+		return nil, fmt.Errorf("不支持的数据库类型: %s (支持: mysql, postgres, mongodb, sqlite)", dbType)
+	}
+}
+
+// connectWithRetry 连接失败时按指数退避重试 DB_CONNECT_RETRIES 次
+// 数据库晚于应用启动时 (如 docker-compose 同时启动) 等待其就绪, 而不是在没有数据库的情况下运行
+func connectWithRetry(connect func() error) error {
+	delay := minConnectRetryDelay
+	for attempt := 1; ; attempt++ {
+		err := connect()
+		if err == nil || attempt > AppConfig.DbConnectRetries {
+			return err
+		}
+		fmt.Printf("⚠️  %v, %s 后重试 (%d/%d)\n", err, delay, attempt, AppConfig.DbConnectRetries)
+		time.Sleep(delay)
+		delay = min(delay*2, maxConnectRetryDelay)
+	}
+}
+
+// openGorm 打开 GORM 连接并设置连接池, 失败时关闭已创建的连接池
+// 关闭 GORM 的自动 Ping, 由这里显式 Ping 主库, 以免只读副本 (复制同一配置) 不可用时初始化失败
+func openGorm(dialector gorm.Dialector, cfg DatabaseConfig) (*gorm.DB, error) {
+	db, err := gorm.Open(dialector, &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		closeGorm(db)
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err == nil {
+		err = sqlDB.Ping()
+	}
+	if err != nil {
+		closeGorm(db)
+		return nil, err
+	}
+	configurePool(sqlDB, cfg)
+	return db, nil
+}
+
+// configurePool 按配置设置连接池
+func configurePool(sqlDB *sql.DB, cfg DatabaseConfig) {
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Second)
+	sqlDB.SetConnMaxIdleTime(time.Duration(cfg.ConnMaxIdleTime) * time.Second)
+}
+
+// closeGorm 关闭 GORM 连接池 (db 可以为 nil)
+func closeGorm(db *gorm.DB) {
+	if db == nil {
+		return
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.Close()
+	}
+}
+
+func openMySQL(cfg DatabaseConfig) (*database, error) {
+	db, err := openGorm(mysql.Open(mysqlDSN(cfg, cfg.Host, cfg.Port)), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("MySQL 连接失败: %v", err)
 	}
+	replicas, err := useReplicas(db, cfg, mysqlReplicaDriver)
+	if err != nil {
+		closeGorm(db)
+		return nil, err
+	}
+	return &database{config: cfg, gorm: db, replicas: replicas}, nil
 }
 
-func initMySQL() error {
-	var err error
-	dsn := fmt.Sprintf(
+func mysqlDSN(cfg DatabaseConfig, host string, port int) string {
+	return fmt.Sprintf(
 		"%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		AppConfig.DbUsername,
-		AppConfig.DbPassword,
-		AppConfig.DbHost,
-		AppConfig.DbPort,
-		AppConfig.DbDatabase,
+		cfg.Username,
+		cfg.Password,
+		host,
+		port,
+		cfg.Database,
 	)
+}
 
-	Db, err = gorm.Open(mysql.Open(dsn), &gorm.Config{})
+func openPostgreSQL(cfg DatabaseConfig) (*database, error) {
+	db, err := openGorm(postgres.Open(postgresDSN(cfg, cfg.Host, cfg.Port)), cfg)
 	if err != nil {
-		return fmt.Errorf("MySQL 连接失败: %v", err)
+		return nil, fmt.Errorf("PostgreSQL 连接失败: %v", err)
 	}
-
-	fmt.Println("✅ MySQL 数据库连接成功")
-	return nil
+	replicas, err := useReplicas(db, cfg, postgresReplicaDriver)
+	if err != nil {
+		closeGorm(db)
+		return nil, err
+	}
+	return &database{config: cfg, gorm: db, replicas: replicas}, nil
 }
 
-func initPostgreSQL() error {
-	var err error
-	dsn := fmt.Sprintf(
+func postgresDSN(cfg DatabaseConfig, host string, port int) string {
+	return fmt.Sprintf(
 		"host=%s user=%s password=%s dbname=%s port=%d sslmode=disable TimeZone=Asia/Shanghai",
-		AppConfig.DbHost,
-		AppConfig.DbUsername,
-		AppConfig.DbPassword,
-		AppConfig.DbDatabase,
-		AppConfig.DbPort,
+		host,
+		cfg.Username,
+		cfg.Password,
+		cfg.Database,
+		port,
 	)
+}
 
-	Db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+// openSQLite 打开 SQLite 数据库 (纯 Go 驱动, 不需要 CGO), 用于本地开发和测试
+// cfg.Database 为文件路径或 :memory:; 不支持只读副本
+func openSQLite(cfg DatabaseConfig) (*database, error) {
+	if len(cfg.Replicas) > 0 {
+		return nil, fmt.Errorf("SQLite 不支持只读副本")
+	}
+
+	// 内存数据库每个连接各自独立, 只使用一个连接并且不关闭, 以免数据丢失
+	if cfg.Database == ":memory:" {
+		cfg.MaxOpenConns, cfg.MaxIdleConns = 1, 1
+		cfg.ConnMaxLifetime, cfg.ConnMaxIdleTime = 0, 0
+	}
+
+	db, err := openGorm(sqlite.Open(sqliteDSN(cfg.Database)), cfg)
 	if err != nil {
-		return fmt.Errorf("PostgreSQL 连接失败: %v", err)
+		return nil, fmt.Errorf("SQLite 连接失败: %v", err)
 	}
+	return &database{config: cfg, gorm: db}, nil
+}
 
-	fmt.Println("✅ PostgreSQL 数据库连接成功")
-	return nil
+// sqliteDSN 启用外键约束, 并在数据库被锁定时等待而不是立即返回 SQLITE_BUSY
+func sqliteDSN(path string) string {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
 }
 
-func initMongoDB() error {
+func openMongoDB(cfg DatabaseConfig) (*database, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	// 构建 MongoDB 连接字符串
 	uri := fmt.Sprintf(
 		"mongodb://%s:%s@%s:%d",
-		AppConfig.DbUsername,
-		AppConfig.DbPassword,
-		AppConfig.DbHost,
-		AppConfig.DbPort,
+		cfg.Username,
+		cfg.Password,
+		cfg.Host,
+		cfg.Port,
 	)
 
 	// 如果没有用户名密码，使用简单连接字符串
-	if AppConfig.DbUsername == "" {
-		uri = fmt.Sprintf("mongodb://%s:%d", AppConfig.DbHost, AppConfig.DbPort)
+	if cfg.Username == "" {
+		uri = fmt.Sprintf("mongodb://%s:%d", cfg.Host, cfg.Port)
 	}
 
-	clientOptions := options.Client().ApplyURI(uri)
+	clientOptions := options.Client().
+		ApplyURI(uri).
+		SetMaxPoolSize(uint64(max(cfg.MaxOpenConns, 0))).
+		SetMaxConnIdleTime(time.Duration(cfg.ConnMaxIdleTime) * time.Second)
 
-	var err error
-	MongoClient, err = mongo.Connect(ctx, clientOptions)
+	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
-		return fmt.Errorf("MongoDB 连接失败: %v", err)
+		return nil, fmt.Errorf("MongoDB 连接失败: %v", err)
 	}
 
 	// 测试连接
-	err = MongoClient.Ping(ctx, nil)
+	err = client.Ping(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("MongoDB Ping 失败: %v", err)
+		client.Disconnect(context.Background())
+		return nil, fmt.Errorf("MongoDB Ping 失败: %v", err)
 	}
 
 	// 设置数据库
-	MongoDB = MongoClient.Database(AppConfig.DbDatabase)
+	return &database{config: cfg, mongoClient: client, mongo: client.Database(cfg.Database)}, nil
+}
 
-	fmt.Println("✅ MongoDB 数据库连接成功")
-	return nil
-}
\ No newline at end of file
+// CloseDatabaseConnection 关闭已初始化的数据库连接
+func CloseDatabaseConnection(ctx context.Context) error {
+	databasesMu.Lock()
+	closing := databases
+	databases = make(map[string]*database)
+	databasesMu.Unlock()
+
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(closing)) {
+		db := closing[name]
+		if db.replicas != nil {
+			db.replicas.close()
+		}
+
+		if db.gorm != nil {
+			sqlDB, err := db.gorm.DB()
+			if err == nil {
+				err = sqlDB.Close()
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("关闭 %s%s 连接失败: %w", db.config.Type, databaseLabel(name), err))
+			}
+		}
+
+		if db.mongoClient != nil {
+			if err := db.mongoClient.Disconnect(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("关闭 MongoDB%s 连接失败: %w", databaseLabel(name), err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
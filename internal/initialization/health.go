@@ -0,0 +1,108 @@
+package initialization
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"app/pkg/rabbitmq"
+)
+
+// errNotConnected 配置了数据库但启动时连接失败
+var errNotConnected = errors.New("数据库未连接")
+
+// healthCheckTimeout 健康检查中 Ping 数据存储的超时时间
+const healthCheckTimeout = 2 * time.Second
+
+// Readiness 依赖组件的连接状态, server 的 /api/health、/api/ready 和 worker 的 /health、/ready 共用
+type Readiness struct {
+	Ready     bool                       `json:"-"`      // 已配置的 RabbitMQ 和数据存储均已连接
+	Status    string                     `json:"status"` // ok / degraded
+	RabbitMQ  rabbitmq.Status            `json:"rabbitmq"`
+	Databases map[string]DatastoreStatus `json:"databases"`
+}
+
+// CheckReadiness 检查 RabbitMQ 和已配置数据存储的连接状态, 未配置的组件不影响就绪状态
+func CheckReadiness(ctx context.Context) Readiness {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	r := Readiness{
+		Ready:     true,
+		Status:    "ok",
+		RabbitMQ:  rabbitmq.Health(),
+		Databases: CheckDatastores(ctx),
+	}
+	if r.RabbitMQ.Enabled && !r.RabbitMQ.Connected {
+		r.Ready = false
+	}
+	for _, db := range r.Databases {
+		if !db.Connected {
+			r.Ready = false
+		}
+	}
+	if !r.Ready {
+		r.Status = "degraded"
+	}
+	return r
+}
+
+// DatastoreStatus 数据存储健康状态
+type DatastoreStatus struct {
+	Type      string `json:"type"`
+	Connected bool   `json:"connected"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+
+	// SQL 连接池状态
+	OpenConnections int `json:"open_connections,omitempty"`
+	InUse           int `json:"in_use,omitempty"`
+	Idle            int `json:"idle,omitempty"`
+
+	// 只读副本状态 (副本不可用时读请求回退到主库, 不影响 Connected)
+	Replicas []ReplicaStatus `json:"replicas,omitempty"`
+}
+
+// CheckDatastores Ping 所有已配置的数据存储, 按连接名称返回状态 (未配置数据库时返回空 map)
+// 配置了数据库但启动时连接失败的, Connected 为 false
+func CheckDatastores(ctx context.Context) map[string]DatastoreStatus {
+	result := make(map[string]DatastoreStatus)
+	for name, cfg := range databaseConfigs() {
+		databasesMu.RLock()
+		db := databases[name]
+		databasesMu.RUnlock()
+		result[name] = checkDatastore(ctx, cfg, db)
+	}
+	return result
+}
+
+// checkDatastore Ping 单个数据存储, db 为 nil 表示未连接
+func checkDatastore(ctx context.Context, cfg DatabaseConfig, db *database) DatastoreStatus {
+	status := DatastoreStatus{Type: cfg.Type}
+	start := time.Now()
+	var err error
+	switch {
+	case db == nil:
+		err = errNotConnected
+	case db.gorm != nil:
+		sqlDB, e := db.gorm.DB()
+		if err = e; err == nil {
+			err = sqlDB.PingContext(ctx)
+			stats := sqlDB.Stats()
+			status.OpenConnections = stats.OpenConnections
+			status.InUse = stats.InUse
+			status.Idle = stats.Idle
+		}
+	case db.mongoClient != nil:
+		err = db.mongoClient.Ping(ctx, nil)
+	}
+	status.LatencyMs = time.Since(start).Milliseconds()
+	if db != nil && db.replicas != nil {
+		status.Replicas = db.replicas.status()
+	}
+	status.Connected = err == nil
+	if err != nil {
+		status.Error = err.Error()
+	}
+	return status
+}
@@ -0,0 +1,20 @@
+package initialization
+
+import (
+	"fmt"
+
+	"app/pkg/outbox"
+	"app/pkg/rabbitmq"
+)
+
+// InitOutboxRelay 启动发件箱 Relay, 未启用、未配置 SQL 数据库或未使用 RabbitMQ 时返回 nil
+// 进程内消息总线不持久化, 发布到其中的发件箱消息会在重启后丢失, 因此不启动 Relay
+// 需要在 InitRabbitmq 之后调用
+func InitOutboxRelay() *outbox.Relay {
+	if !AppConfig.OutboxRelayEnable || Db == nil || rabbitmq.Health().Transport != rabbitmq.TransportAMQP {
+		return nil
+	}
+
+	fmt.Println("✅ Outbox Relay 已启动")
+	return outbox.StartRelay(Db, outbox.Options{})
+}
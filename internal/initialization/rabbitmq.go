@@ -0,0 +1,53 @@
+package initialization
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"app/pkg/rabbitmq"
+)
+
+// RabbitmqConfig 根据应用配置构造 RabbitMQ 连接配置
+func RabbitmqConfig() rabbitmq.Config {
+	return rabbitmq.Config{
+		Transport:          AppConfig.MqTransport,
+		URL:                AppConfig.MqURL,
+		Host:               AppConfig.MqHost,
+		Port:               AppConfig.MqPort,
+		Username:           AppConfig.MqUsername,
+		Password:           AppConfig.MqPassword,
+		Vhost:              AppConfig.MqVhost,
+		Heartbeat:          time.Duration(AppConfig.MqHeartbeat) * time.Second,
+		ConnectionName:     AppConfig.MqConnectionName,
+		TLS:                AppConfig.MqTLS,
+		CACert:             AppConfig.MqCACert,
+		ClientCert:         AppConfig.MqClientCert,
+		ClientKey:          AppConfig.MqClientKey,
+		InsecureSkipVerify: AppConfig.MqTLSSkipVerify,
+	}
+}
+
+// InitRabbitmq 初始化 RabbitMQ 连接 (MQ_TRANSPORT=memory 时使用进程内消息总线)
+// 需要在数据库之后初始化, 以便消费去重记录使用已配置的数据库
+func InitRabbitmq() error {
+	initDedupStore()
+	return rabbitmq.NewRabbitmq(RabbitmqConfig())
+}
+
+// initDedupStore 根据已初始化的数据库配置消费去重存储, 未配置数据库时使用进程内 LRU
+func initDedupStore() {
+	switch {
+	case Db != nil:
+		rabbitmq.SetDedupStore(rabbitmq.NewGormDedupStore(Db))
+	case MongoDB != nil:
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		store, err := rabbitmq.NewMongoDedupStore(ctx, MongoDB)
+		if err != nil {
+			fmt.Printf("⚠️  消费去重: %v, 使用进程内存储\n", err)
+			return
+		}
+		rabbitmq.SetDedupStore(store)
+	}
+}
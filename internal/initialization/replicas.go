@@ -0,0 +1,230 @@
+package initialization
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// 只读副本健康检查
+const (
+	replicaCheckInterval = 10 * time.Second
+	replicaCheckTimeout  = 2 * time.Second
+)
+
+// replicaDriver 创建只读副本连接所需的方言信息
+type replicaDriver struct {
+	driverName string                                                 // database/sql 驱动名
+	dsn        func(cfg DatabaseConfig, host string, port int) string // 连接字符串
+	dialector  func(conn *sql.DB) gorm.Dialector                      // 使用已打开连接池的 Dialector, 初始化时不访问数据库
+}
+
+var mysqlReplicaDriver = replicaDriver{
+	driverName: "mysql",
+	dsn:        mysqlDSN,
+	dialector: func(conn *sql.DB) gorm.Dialector {
+		return mysql.New(mysql.Config{Conn: conn, SkipInitializeWithVersion: true})
+	},
+}
+
+var postgresReplicaDriver = replicaDriver{
+	driverName: "pgx",
+	dsn:        postgresDSN,
+	dialector: func(conn *sql.DB) gorm.Dialector {
+		return postgres.New(postgres.Config{Conn: conn})
+	},
+}
+
+// replicaSet 只读副本: 定期 Ping 各副本, 读请求轮询分配给健康的副本, 全部不可用时回退到主库
+type replicaSet struct {
+	primary gorm.ConnPool
+	members []*replica
+	next    atomic.Uint64
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// replica 单个只读副本
+type replica struct {
+	host    string
+	db      *sql.DB
+	healthy atomic.Bool
+	lastErr atomic.Pointer[string]
+}
+
+// ReplicaStatus 只读副本健康状态
+type ReplicaStatus struct {
+	Host    string `json:"host"`
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+// UsePrimary 强制使用主库 (如写入后立即读取, 避免复制延迟读到旧数据)
+// 事务和写操作总是使用主库; 未配置只读副本时不影响查询
+//
+//	initialization.UsePrimary(initialization.Db).First(&user, id)
+func UsePrimary(db *gorm.DB) *gorm.DB {
+	return db.Clauses(dbresolver.Write)
+}
+
+// useReplicas 按 cfg.Replicas (DB_REPLICAS) 注册只读副本: 读操作使用副本, 写操作和事务使用主库
+// 副本使用与主库相同的用户名、密码和数据库名, 启动时不可用的副本不影响初始化; 未配置副本时返回 nil
+func useReplicas(db *gorm.DB, cfg DatabaseConfig, driver replicaDriver) (*replicaSet, error) {
+	if len(cfg.Replicas) == 0 {
+		return nil, nil
+	}
+
+	set := &replicaSet{primary: db.ConnPool, done: make(chan struct{})}
+	for _, addr := range cfg.Replicas {
+		host, port, err := splitHostPort(addr, cfg.Port)
+		if err != nil {
+			set.close()
+			return nil, err
+		}
+		conn, err := sql.Open(driver.driverName, driver.dsn(cfg, host, port))
+		if err != nil {
+			set.close()
+			return nil, fmt.Errorf("只读副本 %s 配置错误: %v", addr, err)
+		}
+		configurePool(conn, cfg)
+		set.members = append(set.members, &replica{host: net.JoinHostPort(host, strconv.Itoa(port)), db: conn})
+	}
+
+	// 主库作为最后一个副本注册, 只在所有副本都不可用时由 Resolve 选中
+	// (只有一个副本时 dbresolver 不调用 Policy, 因此至少需要两个)
+	dialectors := make([]gorm.Dialector, 0, len(set.members)+1)
+	for _, r := range set.members {
+		dialectors = append(dialectors, driver.dialector(r.db))
+	}
+	primary, ok := db.ConnPool.(*sql.DB)
+	if !ok {
+		set.close()
+		return nil, fmt.Errorf("只读副本: 不支持的主库连接类型 %T", db.ConnPool)
+	}
+	dialectors = append(dialectors, driver.dialector(primary))
+
+	err := db.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: dialectors,
+		Policy:   set,
+	}))
+	if err != nil {
+		set.close()
+		return nil, fmt.Errorf("注册只读副本失败: %v", err)
+	}
+
+	set.check()
+	set.wg.Add(1)
+	go set.run()
+
+	fmt.Printf("✅ 只读副本: %d 个\n", len(set.members))
+	return set, nil
+}
+
+// splitHostPort 解析 host 或 host:port, 未指定端口时使用主库端口
+func splitHostPort(addr string, defaultPort int) (string, int, error) {
+	addr = strings.TrimSpace(addr)
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr, defaultPort, nil
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return "", 0, fmt.Errorf("只读副本地址 %s 端口无效", addr)
+	}
+	return host, port, nil
+}
+
+// Resolve 实现 dbresolver.Policy, 轮询选择健康的副本, 全部不可用时使用主库
+func (s *replicaSet) Resolve([]gorm.ConnPool) gorm.ConnPool {
+	healthy := make([]*replica, 0, len(s.members))
+	for _, r := range s.members {
+		if r.healthy.Load() {
+			healthy = append(healthy, r)
+		}
+	}
+	if len(healthy) == 0 {
+		return s.primary
+	}
+	return healthy[s.next.Add(1)%uint64(len(healthy))].db
+}
+
+// run 定期检查副本健康状态
+func (s *replicaSet) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(replicaCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.check()
+		}
+	}
+}
+
+// check Ping 所有副本, 状态变化时记录日志
+func (s *replicaSet) check() {
+	var wg sync.WaitGroup
+	for _, r := range s.members {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), replicaCheckTimeout)
+			defer cancel()
+			err := r.db.PingContext(ctx)
+
+			healthy := err == nil
+			if healthy {
+				r.lastErr.Store(nil)
+			} else {
+				msg := err.Error()
+				r.lastErr.Store(&msg)
+			}
+			if r.healthy.Swap(healthy) != healthy {
+				if healthy {
+					log.Printf("Database replica %s is healthy, added to rotation", r.host)
+				} else {
+					log.Printf("Database replica %s is unhealthy, removed from rotation: %v", r.host, err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// status 各副本的健康状态
+func (s *replicaSet) status() []ReplicaStatus {
+	result := make([]ReplicaStatus, 0, len(s.members))
+	for _, r := range s.members {
+		st := ReplicaStatus{Host: r.host, Healthy: r.healthy.Load()}
+		if msg := r.lastErr.Load(); msg != nil {
+			st.Error = *msg
+		}
+		result = append(result, st)
+	}
+	return result
+}
+
+// close 停止健康检查并关闭副本连接池
+func (s *replicaSet) close() {
+	close(s.done)
+	s.wg.Wait()
+	for _, r := range s.members {
+		r.db.Close()
+	}
+}
@@ -0,0 +1,48 @@
+package initialization
+
+import (
+	"fmt"
+
+	"app/pkg/scheduler"
+)
+
+// InitScheduler 初始化调度器并加载已注册的定时任务
+func InitScheduler() error {
+	opts := scheduler.Options{
+		Timezone: AppConfig.SchedulerTZ,
+		Seconds:  AppConfig.SchedulerSeconds,
+	}
+	if err := scheduler.Init(opts); err != nil {
+		return err
+	}
+	if err := InitSchedulerBackend(); err != nil {
+		return err
+	}
+	return scheduler.LoadJobs()
+}
+
+// InitSchedulerBackend 根据已初始化的数据库配置分布式锁、执行记录和暂停状态存储
+// 未启动调度器的进程 (如手动执行任务、管理接口) 也需要调用, 以便共享锁、执行历史和暂停状态
+func InitSchedulerBackend() error {
+	switch {
+	case Db != nil && Db.Dialector.Name() == "sqlite":
+		// SQLite 只用于单进程的本地开发, 使用进程内锁
+		scheduler.SetRunStore(scheduler.NewGormRunStore(Db))
+		scheduler.SetPauseStore(scheduler.NewGormPauseStore(Db))
+	case Db != nil:
+		locker, err := scheduler.NewSQLLocker(Db)
+		if err != nil {
+			return fmt.Errorf("创建分布式锁失败: %w", err)
+		}
+		scheduler.SetLocker(locker)
+		scheduler.SetRunStore(scheduler.NewGormRunStore(Db))
+		scheduler.SetPauseStore(scheduler.NewGormPauseStore(Db))
+	case MongoDB != nil:
+		scheduler.SetLocker(scheduler.NewMongoLocker(MongoDB))
+		scheduler.SetRunStore(scheduler.NewMongoRunStore(MongoDB))
+		scheduler.SetPauseStore(scheduler.NewMongoPauseStore(MongoDB))
+	default:
+		fmt.Println("⚠️  Scheduler: 未配置数据库, Singleton 任务仅在当前进程内互斥, 执行记录和暂停状态仅保存在内存中")
+	}
+	return nil
+}
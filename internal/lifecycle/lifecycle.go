@@ -0,0 +1,107 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Hook 关闭钩子
+type Hook struct {
+	Name string                          // 组件名称
+	Stop func(ctx context.Context) error // 关闭函数
+}
+
+// Manager 进程生命周期管理器
+// 监听 SIGINT/SIGTERM, 收到信号后按注册的逆序执行关闭钩子
+type Manager struct {
+	timeout time.Duration
+
+	mu    sync.Mutex
+	hooks []Hook
+	errCh chan error
+}
+
+// New 创建生命周期管理器, timeout 为关闭阶段的总超时时间
+func New(timeout time.Duration) *Manager {
+	return &Manager{
+		timeout: timeout,
+		errCh:   make(chan error, 1),
+	}
+}
+
+// Append 注册关闭钩子 (按初始化顺序注册, 关闭时逆序执行)
+func (m *Manager) Append(name string, stop func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, Hook{Name: name, Stop: stop})
+}
+
+// Go 在后台运行一个阻塞服务, 返回非 nil 错误时触发进程关闭
+func (m *Manager) Go(name string, run func() error) {
+	go func() {
+		if err := run(); err != nil {
+			select {
+			case m.errCh <- fmt.Errorf("%s: %w", name, err):
+			default:
+			}
+		}
+	}()
+}
+
+// Serve 在后台启动 HTTP 服务, 并注册优雅关闭钩子
+func (m *Manager) Serve(name string, srv *http.Server) {
+	m.Go(name, func() error {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	})
+	m.Append(name, srv.Shutdown)
+}
+
+// Wait 阻塞直到收到退出信号或后台服务异常退出, 随后执行关闭流程
+func (m *Manager) Wait() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		fmt.Println("\n收到退出信号, 正在关闭...")
+	case runErr = <-m.errCh:
+		fmt.Printf("\n⚠️  服务异常退出: %v, 正在关闭...\n", runErr)
+	}
+
+	return errors.Join(runErr, m.Shutdown())
+}
+
+// Shutdown 按注册的逆序执行所有关闭钩子
+func (m *Manager) Shutdown() error {
+	m.mu.Lock()
+	hooks := m.hooks
+	m.hooks = nil
+	m.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hook := hooks[i]
+		if err := hook.Stop(ctx); err != nil {
+			fmt.Printf("⚠️  %s 关闭失败: %v\n", hook.Name, err)
+			errs = append(errs, fmt.Errorf("%s: %w", hook.Name, err))
+			continue
+		}
+		fmt.Printf("⏹️  %s: 已关闭\n", hook.Name)
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,236 @@
+package mongomigrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// VersionCollection 记录已执行迁移的集合名称
+const VersionCollection = "schema_migrations"
+
+// MigrationFunc 迁移函数, 用于创建索引或迁移数据
+// MongoDB 单机部署不支持事务, 迁移函数需要可以重复执行 (如 CreateOne 索引、按条件更新)
+type MigrationFunc func(ctx context.Context, db *mongo.Database) error
+
+// Migration 一个 MongoDB 迁移
+type Migration struct {
+	Version int64
+	Name    string
+	Up      MigrationFunc
+	Down    MigrationFunc
+}
+
+// MigrationStatus 迁移的执行状态
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// versionRecord 版本集合中的记录
+type versionRecord struct {
+	Version   int64     `bson:"_id"`
+	Name      string    `bson:"name"`
+	AppliedAt time.Time `bson:"applied_at"`
+}
+
+var migrations = make(map[int64]*Migration)
+
+// Add 注册迁移, 版本号和名称取自调用方的文件名 (如 20261018130000_create_job_runs_indexes.go)
+// 在迁移文件的 init 中调用, 与 goose.AddMigrationContext 相同
+func Add(up, down MigrationFunc) {
+	_, file, _, _ := runtime.Caller(1)
+	name := strings.TrimSuffix(filepath.Base(file), ".go")
+	prefix, rest, _ := strings.Cut(name, "_")
+	version, err := strconv.ParseInt(prefix, 10, 64)
+	if err != nil || version <= 0 {
+		panic(fmt.Sprintf("mongomigrate: 文件名 %s 需要以版本号开头, 如 20261018130000_name.go", filepath.Base(file)))
+	}
+	Register(version, rest, up, down)
+}
+
+// Register 按版本号注册迁移, 版本号重复时 panic
+func Register(version int64, name string, up, down MigrationFunc) {
+	if up == nil {
+		panic(fmt.Sprintf("mongomigrate: 迁移 %d 缺少 Up 函数", version))
+	}
+	if existing, ok := migrations[version]; ok {
+		panic(fmt.Sprintf("mongomigrate: 迁移版本 %d 重复 (%s, %s)", version, existing.Name, name))
+	}
+	migrations[version] = &Migration{Version: version, Name: name, Up: up, Down: down}
+}
+
+// sorted 按版本号排序的已注册迁移
+func sorted() []*Migration {
+	versions := make([]int64, 0, len(migrations))
+	for v := range migrations {
+		versions = append(versions, v)
+	}
+	slices.Sort(versions)
+
+	result := make([]*Migration, 0, len(versions))
+	for _, v := range versions {
+		result = append(result, migrations[v])
+	}
+	return result
+}
+
+// applied 已执行的迁移, 按版本号索引
+func applied(ctx context.Context, db *mongo.Database) (map[int64]versionRecord, error) {
+	cursor, err := db.Collection(VersionCollection).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("读取 %s 失败: %w", VersionCollection, err)
+	}
+
+	var records []versionRecord
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("读取 %s 失败: %w", VersionCollection, err)
+	}
+
+	result := make(map[int64]versionRecord, len(records))
+	for _, r := range records {
+		result[r.Version] = r
+	}
+	return result, nil
+}
+
+// Up 按版本号顺序执行所有未执行的迁移, 某个迁移失败时停止
+func Up(ctx context.Context, db *mongo.Database) error {
+	done, err := applied(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	for _, m := range sorted() {
+		if _, ok := done[m.Version]; ok {
+			continue
+		}
+
+		start := time.Now()
+		if err := m.Up(ctx, db); err != nil {
+			return fmt.Errorf("迁移 %d_%s 失败: %w", m.Version, m.Name, err)
+		}
+		_, err := db.Collection(VersionCollection).InsertOne(ctx, versionRecord{
+			Version:   m.Version,
+			Name:      m.Name,
+			AppliedAt: time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("记录迁移 %d_%s 失败: %w", m.Version, m.Name, err)
+		}
+		log.Printf("OK   %d_%s (%s)", m.Version, m.Name, time.Since(start).Round(time.Millisecond))
+		count++
+	}
+
+	if count == 0 {
+		log.Printf("no migrations to run")
+	}
+	return nil
+}
+
+// Down 回滚最后一个已执行的迁移
+func Down(ctx context.Context, db *mongo.Database) error {
+	done, err := applied(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	var last int64
+	for v := range done {
+		last = max(last, v)
+	}
+	if last == 0 {
+		return errors.New("没有可以回滚的迁移")
+	}
+	return rollback(ctx, db, done[last])
+}
+
+// Reset 按版本号倒序回滚所有已执行的迁移
+func Reset(ctx context.Context, db *mongo.Database) error {
+	done, err := applied(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	versions := make([]int64, 0, len(done))
+	for v := range done {
+		versions = append(versions, v)
+	}
+	slices.Sort(versions)
+	slices.Reverse(versions)
+
+	for _, v := range versions {
+		if err := rollback(ctx, db, done[v]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rollback 执行迁移的 Down 函数并删除版本记录
+func rollback(ctx context.Context, db *mongo.Database, record versionRecord) error {
+	m, ok := migrations[record.Version]
+	if !ok {
+		return fmt.Errorf("迁移 %d_%s 已执行但未注册, 无法回滚", record.Version, record.Name)
+	}
+
+	if m.Down != nil {
+		if err := m.Down(ctx, db); err != nil {
+			return fmt.Errorf("回滚 %d_%s 失败: %w", m.Version, m.Name, err)
+		}
+	}
+	if _, err := db.Collection(VersionCollection).DeleteOne(ctx, bson.M{"_id": m.Version}); err != nil {
+		return fmt.Errorf("删除迁移记录 %d_%s 失败: %w", m.Version, m.Name, err)
+	}
+	log.Printf("OK   rollback %d_%s", m.Version, m.Name)
+	return nil
+}
+
+// Status 所有已注册迁移的执行状态, 按版本号排序
+func Status(ctx context.Context, db *mongo.Database) ([]MigrationStatus, error) {
+	done, err := applied(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []MigrationStatus
+	for _, m := range sorted() {
+		st := MigrationStatus{Migration: *m}
+		if r, ok := done[m.Version]; ok {
+			st.AppliedAt = &r.AppliedAt
+		}
+		result = append(result, st)
+	}
+	return result, nil
+}
+
+// EnsureIndexes 创建索引, 索引已存在时不报错, 供迁移函数使用
+func EnsureIndexes(ctx context.Context, db *mongo.Database, collection string, indexes ...mongo.IndexModel) error {
+	if _, err := db.Collection(collection).Indexes().CreateMany(ctx, indexes); err != nil {
+		return fmt.Errorf("创建 %s 索引失败: %w", collection, err)
+	}
+	return nil
+}
+
+// DropIndexes 按名称删除索引, 索引不存在时不报错, 供迁移函数使用
+func DropIndexes(ctx context.Context, db *mongo.Database, collection string, names ...string) error {
+	for _, name := range names {
+		_, err := db.Collection(collection).Indexes().DropOne(ctx, name)
+		var cmdErr mongo.CommandError
+		if err != nil && !(errors.As(err, &cmdErr) && (cmdErr.Name == "IndexNotFound" || cmdErr.Name == "NamespaceNotFound")) {
+			return fmt.Errorf("删除 %s 索引 %s 失败: %w", collection, name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+// Package outbox 事务发件箱: 消息与业务数据在同一个数据库事务中写入 outbox_messages 表,
+// 由 Relay 异步发布到 RabbitMQ, 避免写库成功但消息丢失 (或反之) 的不一致
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"app/pkg/rabbitmq"
+)
+
+// 消息状态
+const (
+	StatusPending = "pending" // 待发布
+	StatusSent    = "sent"    // 已发布 (Broker 已确认)
+)
+
+// Message 发件箱消息 (outbox_messages 表, 由 db/migrations 创建)
+type Message struct {
+	ID            uint64     `json:"id" gorm:"primaryKey"`
+	MessageID     string     `json:"message_id"`
+	Exchange      string     `json:"exchange"`    // 为空时发布到 RoutingKey 同名队列
+	RoutingKey    string     `json:"routing_key"` // 路由键或队列名称
+	ContentType   string     `json:"content_type"`
+	Headers       string     `json:"headers"` // JSON 格式的消息头
+	CorrelationID string     `json:"correlation_id"`
+	Priority      uint8      `json:"priority"`
+	TTLMs         int64      `json:"ttl_ms"`
+	Transient     bool       `json:"transient"`
+	Body          []byte     `json:"body"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SentAt        *time.Time `json:"sent_at"`
+}
+
+// TableName 表名
+func (Message) TableName() string {
+	return "outbox_messages"
+}
+
+// Enqueue 在事务中写入一条发往指定队列的消息, 事务提交后由 Relay 发布
+//
+//	initialization.Db.Transaction(func(tx *gorm.DB) error {
+//		if err := tx.Create(&order).Error; err != nil {
+//			return err
+//		}
+//		return outbox.Enqueue(tx, "order_created", body, rabbitmq.PublishOptions{})
+//	})
+func Enqueue(tx *gorm.DB, queue string, body []byte, opts rabbitmq.PublishOptions) error {
+	return EnqueueTo(tx, "", queue, body, opts)
+}
+
+// EnqueueTo 在事务中写入一条发往指定交换机的消息
+// 消息头以 JSON 存储, 数字类型的值发布时会变为 float64
+func EnqueueTo(tx *gorm.DB, exchange, routingKey string, body []byte, opts rabbitmq.PublishOptions) error {
+	msg := Message{
+		MessageID:     opts.MessageID,
+		Exchange:      exchange,
+		RoutingKey:    routingKey,
+		ContentType:   opts.ContentType,
+		CorrelationID: opts.CorrelationID,
+		Priority:      opts.Priority,
+		TTLMs:         opts.TTL.Milliseconds(),
+		Transient:     opts.Transient,
+		Body:          body,
+		Status:        StatusPending,
+		NextAttemptAt: time.Now(),
+		CreatedAt:     time.Now(),
+	}
+	if msg.MessageID == "" {
+		msg.MessageID = uuid.NewString()
+	}
+	if len(opts.Headers) > 0 {
+		headers, err := json.Marshal(opts.Headers)
+		if err != nil {
+			return fmt.Errorf("marshal outbox headers: %w", err)
+		}
+		msg.Headers = string(headers)
+	}
+	return tx.Create(&msg).Error
+}
+
+// publishOptions 还原发布选项
+func (m *Message) publishOptions() (rabbitmq.PublishOptions, error) {
+	opts := rabbitmq.PublishOptions{
+		ContentType:   m.ContentType,
+		MessageID:     m.MessageID,
+		CorrelationID: m.CorrelationID,
+		TTL:           time.Duration(m.TTLMs) * time.Millisecond,
+		Priority:      m.Priority,
+		Transient:     m.Transient,
+	}
+	if m.Headers != "" {
+		if err := json.Unmarshal([]byte(m.Headers), &opts.Headers); err != nil {
+			return opts, fmt.Errorf("unmarshal outbox headers: %w", err)
+		}
+	}
+	return opts, nil
+}
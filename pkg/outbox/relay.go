@@ -0,0 +1,260 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"app/pkg/rabbitmq"
+)
+
+// Options Relay 选项
+type Options struct {
+	BatchSize    int           // 每批发布的消息数, 默认 100
+	PollInterval time.Duration // 没有待发布消息时的轮询间隔, 默认 1s
+	MaxBackoff   time.Duration // Broker 不可用或单条消息发布失败时的最大退避时间, 默认 30s
+	Retention    time.Duration // 已发布消息的保留时间, 默认 7 天, 小于 0 表示不清理
+	ClaimTimeout time.Duration // 领取的消息的租约时长, 默认 5m; 每批最多发布租约的一半时间, 租约过期后其他实例可以重新领取
+}
+
+// withDefaults 填充默认值
+func (opts Options) withDefaults() Options {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = 30 * time.Second
+	}
+	if opts.Retention == 0 {
+		opts.Retention = 7 * 24 * time.Hour
+	}
+	if opts.ClaimTimeout <= 0 {
+		opts.ClaimTimeout = 5 * time.Minute
+	}
+	return opts
+}
+
+// cleanupInterval 清理已发布消息的间隔
+const cleanupInterval = time.Hour
+
+// Relay 轮询发件箱并发布待发布的消息
+// 多个实例可以同时运行: 每批消息在短事务中使用 SELECT ... FOR UPDATE SKIP LOCKED 领取,
+// 并将 next_attempt_at 推迟 ClaimTimeout 作为租约, 发布时不持有行锁和数据库连接
+type Relay struct {
+	db     *gorm.DB
+	opts   Options
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// StartRelay 启动 Relay 协程
+func StartRelay(db *gorm.DB, opts Options) *Relay {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &Relay{
+		db:     db,
+		opts:   opts.withDefaults(),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go r.run(ctx)
+	return r
+}
+
+// Shutdown 停止 Relay, 等待正在发布的批次完成
+func (r *Relay) Shutdown(ctx context.Context) error {
+	r.cancel()
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *Relay) run(ctx context.Context) {
+	defer close(r.done)
+
+	backoff := r.opts.PollInterval
+	var lastCleanup time.Time
+	for {
+		delay := r.opts.PollInterval
+		n, err := r.relayBatch(ctx)
+		switch {
+		case err != nil:
+			log.Printf("outbox: relay failed, retry in %s: %v", backoff, err)
+			delay = backoff
+			backoff = min(backoff*2, r.opts.MaxBackoff)
+		case n == r.opts.BatchSize:
+			// 可能还有积压, 立即处理下一批
+			delay = 0
+			backoff = r.opts.PollInterval
+		default:
+			backoff = r.opts.PollInterval
+		}
+
+		if r.opts.Retention > 0 && time.Since(lastCleanup) > cleanupInterval {
+			r.cleanup(ctx)
+			lastCleanup = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// relayBatch 领取一批到期的待发布消息并逐条发布, 返回领取的条数
+// Broker 未连接时返回 rabbitmq.ErrNotConnected, 由调用方退避
+func (r *Relay) relayBatch(ctx context.Context) (int, error) {
+	if !rabbitmq.Health().Connected {
+		return 0, rabbitmq.ErrNotConnected
+	}
+
+	// 停止时完成正在发布的消息并记录结果, 尚未发布的消息释放给其他实例
+	stop := ctx
+	ctx = context.WithoutCancel(ctx)
+
+	msgs, err := r.claim(ctx)
+	if err != nil || len(msgs) == 0 {
+		return 0, err
+	}
+
+	// 租约过半后不再发布, 避免租约过期后被其他实例重复领取
+	deadline := time.Now().Add(r.opts.ClaimTimeout / 2)
+
+	var brokerErr error
+	for i := range msgs {
+		if brokerErr != nil || stop.Err() != nil || time.Now().After(deadline) {
+			if err := r.release(ctx, msgs[i:]); err != nil {
+				return len(msgs), err
+			}
+			break
+		}
+
+		m := &msgs[i]
+		publishCtx, cancel := context.WithDeadline(ctx, deadline)
+		err := publish(publishCtx, m)
+		cancel()
+		if err != nil {
+			m.Attempts++
+			next := time.Now().Add(r.retryDelay(m.Attempts))
+			log.Printf("outbox: publish message %s failed (attempt %d), retry at %s: %v", m.MessageID, m.Attempts, next.Format(time.RFC3339), err)
+			update := r.db.WithContext(ctx).Model(m).Updates(map[string]any{
+				"attempts":        m.Attempts,
+				"last_error":      err.Error(),
+				"next_attempt_at": next,
+			})
+			if update.Error != nil {
+				return len(msgs), update.Error
+			}
+			if errors.Is(err, rabbitmq.ErrNotConnected) {
+				// 释放剩余的消息后再退避
+				brokerErr = err
+			}
+			continue
+		}
+
+		update := r.db.WithContext(ctx).Model(m).Updates(map[string]any{
+			"status":   StatusSent,
+			"attempts": m.Attempts + 1,
+			"sent_at":  time.Now(),
+		})
+		if update.Error != nil {
+			return len(msgs), update.Error
+		}
+	}
+	return len(msgs), brokerErr
+}
+
+// claim 在短事务中锁定一批到期的待发布消息, 并将 next_attempt_at 推迟 ClaimTimeout 作为租约
+// 事务提交后行锁即释放, 租约期间其他实例不会领取这些消息; 实例异常退出时租约过期后重新发布
+func (r *Relay) claim(ctx context.Context) ([]Message, error) {
+	var msgs []Message
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := lockPending(tx).
+			Where("status = ? AND next_attempt_at <= ?", StatusPending, time.Now()).
+			Order("id").
+			Limit(r.opts.BatchSize).
+			Find(&msgs).Error
+		if err != nil || len(msgs) == 0 {
+			return err
+		}
+		return tx.Model(&Message{}).
+			Where("id IN ?", messageIDs(msgs)).
+			Update("next_attempt_at", time.Now().Add(r.opts.ClaimTimeout)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return msgs, nil
+}
+
+// release 释放已领取但未发布的消息, 其他实例可以立即领取
+func (r *Relay) release(ctx context.Context, msgs []Message) error {
+	return r.db.WithContext(ctx).Model(&Message{}).
+		Where("id IN ? AND status = ?", messageIDs(msgs), StatusPending).
+		Update("next_attempt_at", time.Now()).Error
+}
+
+// messageIDs 消息主键列表
+func messageIDs(msgs []Message) []uint64 {
+	ids := make([]uint64, len(msgs))
+	for i, m := range msgs {
+		ids[i] = m.ID
+	}
+	return ids
+}
+
+// publish 发布单条消息并等待 Broker 确认
+func publish(ctx context.Context, m *Message) error {
+	opts, err := m.publishOptions()
+	if err != nil {
+		return err
+	}
+	if m.Exchange == "" {
+		return rabbitmq.Publish(ctx, m.RoutingKey, m.Body, opts)
+	}
+	return rabbitmq.PublishTo(ctx, m.Exchange, m.RoutingKey, m.Body, opts)
+}
+
+// retryDelay 单条消息第 attempts 次失败后的等待时间
+func (r *Relay) retryDelay(attempts int) time.Duration {
+	delay := r.opts.PollInterval
+	for i := 1; i < attempts && delay < r.opts.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, r.opts.MaxBackoff)
+}
+
+// cleanup 删除超过保留时间的已发布消息
+func (r *Relay) cleanup(ctx context.Context) {
+	result := r.db.WithContext(ctx).
+		Where("status = ? AND sent_at < ?", StatusSent, time.Now().Add(-r.opts.Retention)).
+		Delete(&Message{})
+	if result.Error != nil {
+		log.Printf("outbox: cleanup failed: %v", result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("outbox: removed %d sent messages", result.RowsAffected)
+	}
+}
+
+// lockPending 锁定领取的消息, 其他实例跳过已锁定的行 (MySQL 8.0+ / PostgreSQL 9.5+)
+func lockPending(tx *gorm.DB) *gorm.DB {
+	switch tx.Dialector.Name() {
+	case "mysql", "postgres":
+		return tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+	default:
+		return tx
+	}
+}
@@ -0,0 +1,138 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"gorm.io/gorm"
+
+	"app/pkg/rabbitmq"
+)
+
+var memoryOnce sync.Once
+
+// setup 使用 SQLite 内存数据库和进程内消息总线创建 Relay (不启动轮询协程)
+func setup(t *testing.T) (*Relay, *gorm.DB) {
+	t.Helper()
+
+	memoryOnce.Do(func() {
+		if err := rabbitmq.NewRabbitmq(rabbitmq.Config{Transport: rabbitmq.TransportMemory}); err != nil {
+			t.Fatalf("NewRabbitmq: %v", err)
+		}
+	})
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&Message{}); err != nil {
+		t.Fatal(err)
+	}
+	return &Relay{db: db, opts: Options{}.withDefaults()}, db
+}
+
+// enqueue 写入一条待发布消息
+func enqueue(t *testing.T, db *gorm.DB, exchange, routingKey, body string) {
+	t.Helper()
+	if err := EnqueueTo(db, exchange, routingKey, []byte(body), rabbitmq.PublishOptions{}); err != nil {
+		t.Fatalf("EnqueueTo: %v", err)
+	}
+}
+
+func TestRelayBatch(t *testing.T) {
+	r, db := setup(t)
+
+	// 队列名称每次不同, 避免 -count 重复运行时上一次的消费者抢走消息
+	queue := fmt.Sprintf("test.outbox.batch.%d", time.Now().UnixNano())
+	received := make(chan amqp.Delivery, 2)
+	rabbitmq.Consume(queue, func(ctx context.Context, d amqp.Delivery) error {
+		received <- d
+		return nil
+	})
+
+	enqueue(t, db, "", queue, "one")
+	enqueue(t, db, "test.outbox.missing", "x", "lost")
+	enqueue(t, db, "", queue, "two")
+
+	n, err := r.relayBatch(context.Background())
+	if err != nil || n != 3 {
+		t.Fatalf("relayBatch = %d, %v", n, err)
+	}
+	for _, want := range []string{"one", "two"} {
+		select {
+		case d := <-received:
+			if string(d.Body) != want {
+				t.Fatalf("got %q, want %q", d.Body, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("message %q not delivered", want)
+		}
+	}
+
+	var msgs []Message
+	db.Order("id").Find(&msgs)
+	if msgs[0].Status != StatusSent || msgs[2].Status != StatusSent {
+		t.Fatalf("statuses = %s, %s, want sent", msgs[0].Status, msgs[2].Status)
+	}
+	failed := msgs[1]
+	if failed.Status != StatusPending || failed.Attempts != 1 || failed.LastError == "" || !failed.NextAttemptAt.After(time.Now()) {
+		t.Fatalf("failed message = %+v, want pending with retry scheduled", failed)
+	}
+}
+
+func TestRelayClaimLease(t *testing.T) {
+	r, db := setup(t)
+	ctx := context.Background()
+
+	enqueue(t, db, "", "test.outbox.lease", "one")
+	enqueue(t, db, "", "test.outbox.lease", "two")
+
+	// 领取后推迟 next_attempt_at 作为租约, 租约期间不会被再次领取
+	msgs, err := r.claim(ctx)
+	if err != nil || len(msgs) != 2 {
+		t.Fatalf("claim = %d, %v", len(msgs), err)
+	}
+	var leased Message
+	db.First(&leased, msgs[0].ID)
+	if !leased.NextAttemptAt.After(time.Now().Add(r.opts.ClaimTimeout / 2)) {
+		t.Fatalf("next_attempt_at = %s, want a lease of %s", leased.NextAttemptAt, r.opts.ClaimTimeout)
+	}
+	if again, _ := r.claim(ctx); len(again) != 0 {
+		t.Fatalf("claimed %d leased messages again", len(again))
+	}
+
+	// 释放后可以立即领取
+	if err := r.release(ctx, msgs[1:]); err != nil {
+		t.Fatal(err)
+	}
+	again, _ := r.claim(ctx)
+	if len(again) != 1 || again[0].ID != msgs[1].ID {
+		t.Fatalf("claim after release = %+v", again)
+	}
+}
+
+func TestRelayBatchStopped(t *testing.T) {
+	r, db := setup(t)
+
+	enqueue(t, db, "", "test.outbox.stopped", "one")
+
+	// 已停止时不再发布, 领取的消息释放给其他实例
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := r.relayBatch(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var m Message
+	db.First(&m)
+	if m.Status != StatusPending || m.Attempts != 0 || m.NextAttemptAt.After(time.Now()) {
+		t.Fatalf("message = %+v, want released", m)
+	}
+}
@@ -4,143 +4,130 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
+// RabbitmqClient 当前连接 (断线重连后会被替换)
 var RabbitmqClient *amqp.Connection
+
+// RabbitmqChannel 当前默认 Channel (断线重连后会被替换)
 var RabbitmqChannel *amqp.Channel
 
 // NewRabbitmq 初始化 RabbitMQ 连接
-func NewRabbitmq(host string, port int) error {
-	// 检查 MQ 配置是否为空
-	if host == "" {
-		fmt.Println("⏭️  RabbitMQ 配置为空，跳过初始化")
+// 首次连接失败时返回错误, 并在后台持续重连; 配置错误 (如证书无法加载) 时直接返回错误
+func NewRabbitmq(config Config) error {
+	switch config.Transport {
+	case TransportNone:
+		fmt.Println("⏭️  消息队列已禁用 (MQ_TRANSPORT=none)，跳过初始化")
 		return nil
+	case TransportMemory:
+		return useMemoryTransport()
+	case "", TransportAMQP:
+		// 未配置 RabbitMQ 时不初始化, 发布返回 ErrNotConnected
+		// 不自动使用进程内消息总线, 以免生产环境漏配地址时消息 (如发件箱) 被静默丢失
+		if !config.Enabled() {
+			if config.Transport == TransportAMQP {
+				return fmt.Errorf("MQ_TRANSPORT=amqp 但未配置 RabbitMQ 地址")
+			}
+			fmt.Println("⏭️  RabbitMQ 配置为空，跳过初始化")
+			return nil
+		}
+	default:
+		return fmt.Errorf("未知的消息队列传输层: %s", config.Transport)
 	}
 
-	fmt.Println("正在初始化 RabbitMQ 连接...")
-	amqpHost := fmt.Sprintf("amqp://guest:guest@%s:%d/", host, port)
-	conn, err := amqp.Dial(amqpHost)
+	amqpConfig, err := config.amqpConfig()
 	if err != nil {
-		return fmt.Errorf("RabbitMQ 连接失败: %v", err)
+		return err
 	}
-	RabbitmqClient = conn
 
-	ch, err := conn.Channel()
-	if err != nil {
-		return fmt.Errorf("RabbitMQ Channel 创建失败: %v", err)
+	fmt.Printf("正在初始化 RabbitMQ 连接 (%s)...\n", config.redactedURL())
+	sv = newSupervisor(config.dialURL(), amqpConfig)
+	setTransport(&amqpTransport{sv: sv})
+	if err := sv.connect(); err != nil {
+		go sv.reconnect()
+		return fmt.Errorf("%v (后台重连中)", err)
 	}
-	RabbitmqChannel = ch
 
 	fmt.Println("✅ RabbitMQ 连接成功")
 	return nil
 }
 
-// Close 关闭 RabbitMQ 连接
-func Close() {
-	if RabbitmqChannel != nil {
-		RabbitmqChannel.Close()
-	}
-	if RabbitmqClient != nil {
-		RabbitmqClient.Close()
+// useMemoryTransport 启用进程内消息总线, 并声明已注册的交换机和绑定 (与 amqp 连接成功后相同)
+func useMemoryTransport() error {
+	setTransport(newMemoryTransport())
+	if err := transport.Declare(applyTopology); err != nil {
+		return fmt.Errorf("声明交换机和绑定失败: %w", err)
 	}
+	fmt.Println("⚠️  使用进程内消息总线 (MQ_TRANSPORT=memory, 消息不持久化，不跨进程，仅用于本地开发和测试)")
+	return nil
 }
 
-// Send 发送消息到指定队列
-func Send(queueName string, data string) {
-	ch := RabbitmqChannel
-
-	q, err := ch.QueueDeclare(
-		queueName, // name
-		true,      // durable - 持久化
-		true,      // delete when unused - 自动删除
-		false,     // exclusive
-		false,     // no-wait
-		nil,       // arguments
-	)
-	failOnError(err, "Failed to declare a queue")
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	err = ch.PublishWithContext(ctx,
-		"",     // exchange
-		q.Name, // routing key
-		false,  // mandatory
-		false,  // immediate
-		amqp.Publishing{
-			ContentType:  "text/plain",
-			Body:         []byte(data),
-			DeliveryMode: amqp.Persistent, // 消息持久化
-		})
-	failOnError(err, "Failed to publish a message")
-	log.Printf(" [x] Sent %s\n", data)
+// Close 关闭 RabbitMQ 连接
+func Close() {
+	if transport != nil {
+		transport.Close()
+	}
 }
 
-// StartQueue 启动队列监听，支持自定义队列名和处理函数
-func StartQueue(queueName string, handler func([]byte) error) {
-	ch := RabbitmqChannel
-
-	q, err := ch.QueueDeclare(
-		queueName, // name
-		true,      // durable - 持久化
-		true,      // delete when unused - 自动删除
-		false,     // exclusive
-		false,     // no-wait
-		nil,       // arguments
-	)
-	failOnError(err, "Failed to declare a queue")
-
-	err = ch.Qos(
-		1,     // prefetch count
-		0,     // prefetch size
-		false, // global
-	)
-	failOnError(err, "Failed to set QoS")
-
-	msgs, err := ch.Consume(
-		q.Name, // queue
-		"",     // consumer
-		false,  // auto-ack
-		false,  // exclusive
-		false,  // no-local
-		false,  // no-wait
-		nil,    // args
-	)
-	failOnError(err, "Failed to register a consumer")
+// Shutdown 优雅关闭: 取消所有消费者, 等待处理中的消息完成后关闭连接
+func Shutdown(ctx context.Context) error {
+	if transport == nil {
+		return nil
+	}
 
-	go func() {
-		for d := range msgs {
-			log.Printf("Received a message from queue [%s]: %s", queueName, d.Body)
-
-			// 调用业务处理函数
-			if err := handler(d.Body); err != nil {
-				log.Printf("Error processing message: %v", err)
-				d.Nack(false, true) // 消息处理失败，重新入队
-			} else {
-				d.Ack(false) // 消息处理成功，确认
-				log.Printf("Message processed successfully")
+	consumerMu.Lock()
+	shuttingDown = true
+	for _, c := range consumers {
+		if c.sub != nil {
+			if err := c.sub.Cancel(); err != nil {
+				log.Printf("Failed to cancel consumer %s: %s", c.tag, err)
 			}
 		}
+	}
+	consumers = nil
+	consumerMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		consumerWg.Wait()
+		close(done)
 	}()
 
-	log.Printf(" [*] Listening on queue: %s", queueName)
+	select {
+	case <-done:
+	case <-ctx.Done():
+		cancelConsume()
+		Close()
+		return fmt.Errorf("等待消费者处理完成超时: %w", ctx.Err())
+	}
+
+	Close()
+	return nil
+}
+
+// Send 发送消息到指定队列 (失败时只记录日志, 需要感知发送结果请使用 Publish)
+func Send(queueName string, data string) {
+	err := Publish(context.Background(), queueName, []byte(data), PublishOptions{})
+	if err != nil {
+		failOnError(err, "Failed to publish a message")
+		return
+	}
+	log.Printf(" [x] Sent %s\n", data)
 }
 
-// ListenQueue 启动队列监听
+// ListenQueue 启动通过 Register 注册的队列监听
 func ListenQueue() {
-	// 检查 RabbitMQ 是否已初始化
-	if RabbitmqChannel == nil {
+	// 检查消息队列是否已初始化
+	if transport == nil {
 		return
 	}
 
-	// 启动示例队列监听
-	// 使用示例: StartQueue("your_queue_name", YourHandler)
-	// StartQueue("demo_queue", DemoHandler)
+	// 注册示例: Register(Consumer{Queue: "demo_queue", Run: DemoHandler})
+	n := StartConsumers()
 
-	fmt.Println("✅ 队列监听已启动")
+	fmt.Printf("✅ 队列监听已启动 (%d 个消费者)\n", n)
 }
 
 // DemoHandler 示例消息处理函数
@@ -0,0 +1,159 @@
+package rabbitmq
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// defaultHeartbeat 默认心跳间隔
+const defaultHeartbeat = 10 * time.Second
+
+// Config RabbitMQ 连接配置
+type Config struct {
+	Transport string // 传输层: amqp / memory / none, 为空时配置了 MQ_HOST (或 MQ_URL) 使用 amqp, 否则不启用
+
+	URL string // 完整连接地址 (amqp:// 或 amqps://), 设置后忽略 Host/Port/Username/Password/Vhost
+
+	Host     string
+	Port     int
+	Username string // 默认 guest (guest 用户只允许从 localhost 连接)
+	Password string
+	Vhost    string // 默认 /
+
+	Heartbeat      time.Duration // 心跳间隔, 默认 10s
+	ConnectionName string        // 连接名称, 显示在管理界面, 默认 <程序名>@<主机名>
+
+	TLS                bool   // 使用 amqps 连接
+	CACert             string // CA 证书路径, 为空时使用系统证书
+	ClientCert         string // 客户端证书路径 (双向认证)
+	ClientKey          string // 客户端私钥路径 (双向认证)
+	InsecureSkipVerify bool   // 跳过服务端证书校验 (仅用于测试环境)
+}
+
+// Enabled 是否配置了 RabbitMQ
+func (c Config) Enabled() bool {
+	return c.URL != "" || c.Host != ""
+}
+
+// dialURL 连接地址
+func (c Config) dialURL() string {
+	if c.URL != "" {
+		return c.URL
+	}
+
+	scheme, port := "amqp", c.Port
+	if c.TLS {
+		scheme = "amqps"
+	}
+	if port == 0 {
+		port = 5672
+		if c.TLS {
+			port = 5671
+		}
+	}
+	username := c.Username
+	if username == "" {
+		username = "guest"
+		if c.Password == "" {
+			c.Password = "guest"
+		}
+	}
+
+	u := url.URL{
+		Scheme: scheme,
+		User:   url.UserPassword(username, c.Password),
+		Host:   net.JoinHostPort(c.Host, strconv.Itoa(port)),
+		Path:   "/",
+	}
+	if c.Vhost != "" && c.Vhost != "/" {
+		// vhost 需要转义, 例如 "/prod" 表示为 "/%2Fprod"
+		u.Path = "/" + c.Vhost
+		u.RawPath = "/" + url.PathEscape(c.Vhost)
+	}
+	return u.String()
+}
+
+// redactedURL 隐藏密码的连接地址, 用于日志
+func (c Config) redactedURL() string {
+	u, err := url.Parse(c.dialURL())
+	if err != nil {
+		return "<invalid url>"
+	}
+	return u.Redacted()
+}
+
+// amqpConfig 转换为 amqp091 的连接配置
+func (c Config) amqpConfig() (amqp.Config, error) {
+	heartbeat := c.Heartbeat
+	if heartbeat <= 0 {
+		heartbeat = defaultHeartbeat
+	}
+
+	name := c.ConnectionName
+	if name == "" {
+		host, _ := os.Hostname()
+		name = fmt.Sprintf("%s@%s", filepath.Base(os.Args[0]), host)
+	}
+
+	props := amqp.NewConnectionProperties()
+	props.SetClientConnectionName(name)
+
+	cfg := amqp.Config{
+		Heartbeat:  heartbeat,
+		Locale:     "en_US",
+		Properties: props,
+	}
+
+	u, err := url.Parse(c.dialURL())
+	if err != nil {
+		return cfg, fmt.Errorf("RabbitMQ 连接地址无效: %w", err)
+	}
+	if c.TLS || u.Scheme == "amqps" {
+		if cfg.TLSClientConfig, err = c.tlsConfig(); err != nil {
+			return cfg, err
+		}
+	}
+	return cfg, nil
+}
+
+// tlsConfig 构造 TLS 配置
+func (c Config) tlsConfig() (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	if c.CACert != "" {
+		pem, err := os.ReadFile(c.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("读取 CA 证书失败: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA 证书 %s 格式错误", c.CACert)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if c.ClientCert != "" || c.ClientKey != "" {
+		if c.ClientCert == "" || c.ClientKey == "" {
+			return nil, errors.New("客户端证书和私钥需要同时配置")
+		}
+		cert, err := tls.LoadX509KeyPair(c.ClientCert, c.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("加载客户端证书失败: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
@@ -0,0 +1,280 @@
+package rabbitmq
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// 重连退避时间
+const (
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 30 * time.Second
+)
+
+// Status 连接健康状态
+type Status struct {
+	Enabled    bool      `json:"enabled"`              // 是否配置了 RabbitMQ
+	Connected  bool      `json:"connected"`            // 当前是否已连接
+	Reconnects int       `json:"reconnects"`           // 累计重连成功次数
+	LastError  string    `json:"last_error,omitempty"` // 最近一次连接错误
+	Since      time.Time `json:"since"`                // 当前状态开始时间
+	Transport  string    `json:"transport,omitempty"`  // 传输层: amqp / memory
+}
+
+// supervisor 连接守护: 监听连接关闭事件, 断开后按指数退避重连并恢复消费者
+type supervisor struct {
+	url    string
+	config amqp.Config
+
+	mu      sync.RWMutex
+	conn    *amqp.Connection
+	ch      *amqp.Channel // 默认 Channel (消费者使用)
+	pub     *amqp.Channel // 发布 Channel (Confirm 模式)
+	status  Status
+	closing bool
+	done    chan struct{}
+}
+
+var sv *supervisor
+
+// newSupervisor 创建连接守护
+func newSupervisor(url string, config amqp.Config) *supervisor {
+	return &supervisor{
+		url:    url,
+		config: config,
+		status: Status{Enabled: true, Transport: TransportAMQP, Since: time.Now()},
+		done:   make(chan struct{}),
+	}
+}
+
+// connect 建立连接和默认 Channel, 并启动关闭事件监听
+func (s *supervisor) connect() error {
+	conn, err := amqp.DialConfig(s.url, s.config)
+	if err != nil {
+		s.setError(err)
+		return fmt.Errorf("RabbitMQ 连接失败: %v", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		s.setError(err)
+		return fmt.Errorf("RabbitMQ Channel 创建失败: %v", err)
+	}
+
+	pub, err := openPublisher(conn)
+	if err != nil {
+		conn.Close()
+		s.setError(err)
+		return fmt.Errorf("RabbitMQ 发布 Channel 创建失败: %v", err)
+	}
+
+	// 声明代码中注册的交换机和绑定, 失败时只记录日志, 不影响连接
+	if err := withChannel(conn, applyTopology); err != nil {
+		log.Printf("Failed to apply topology: %s", err)
+	}
+	resetDeclarations()
+
+	s.mu.Lock()
+	s.conn = conn
+	s.ch = ch
+	s.pub = pub
+	s.status.Connected = true
+	s.status.LastError = ""
+	s.status.Since = time.Now()
+	s.mu.Unlock()
+
+	RabbitmqClient = conn
+	RabbitmqChannel = ch
+
+	go s.watch(conn, ch, pub)
+	return nil
+}
+
+// openPublisher 打开 Confirm 模式的发布 Channel
+func openPublisher(conn *amqp.Connection) (*amqp.Channel, error) {
+	pub, err := conn.Channel()
+	if err != nil {
+		return nil, err
+	}
+	if err := pub.Confirm(false); err != nil {
+		pub.Close()
+		return nil, err
+	}
+	return pub, nil
+}
+
+// watch 等待连接或默认 Channel 关闭, 非主动关闭时触发重连
+func (s *supervisor) watch(conn *amqp.Connection, ch, pub *amqp.Channel) {
+	go s.watchPublisher(conn, pub)
+
+	connClosed := conn.NotifyClose(make(chan *amqp.Error, 1))
+	chClosed := ch.NotifyClose(make(chan *amqp.Error, 1))
+
+	var reason *amqp.Error
+	select {
+	case reason = <-connClosed:
+	case reason = <-chClosed:
+		// Channel 异常关闭时连接可能仍然存活, 关闭连接后统一重连
+		conn.Close()
+	case <-s.done:
+		return
+	}
+
+	if s.isClosing() {
+		return
+	}
+
+	if reason != nil {
+		log.Printf("RabbitMQ connection lost: %s", reason)
+		s.setError(reason)
+	} else {
+		s.setError(amqp.ErrClosed)
+	}
+	s.reconnect()
+}
+
+// watchPublisher 发布 Channel 异常关闭 (如发布到不存在的交换机) 时只重新打开发布 Channel, 不中断消费者
+// 连接已断开时退出, 由 watch 负责重连
+func (s *supervisor) watchPublisher(conn *amqp.Connection, pub *amqp.Channel) {
+	for {
+		// Channel 在注册前已关闭时通知 Channel 直接关闭, reason 为 nil
+		reason := <-pub.NotifyClose(make(chan *amqp.Error, 1))
+		if conn.IsClosed() || s.isClosing() {
+			return
+		}
+		if reason != nil {
+			log.Printf("RabbitMQ publish channel closed: %s", reason)
+		}
+
+		next, err := openPublisher(conn)
+		if err != nil {
+			log.Printf("Failed to reopen publish channel: %s", err)
+			conn.Close()
+			return
+		}
+
+		s.mu.Lock()
+		s.pub = next
+		s.mu.Unlock()
+		pub = next
+	}
+}
+
+// reconnect 按指数退避重连, 成功后恢复所有消费者
+func (s *supervisor) reconnect() {
+	delay := minReconnectDelay
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-time.After(delay):
+		}
+
+		if err := s.connect(); err != nil {
+			log.Printf("RabbitMQ reconnect failed, retry in %s: %s", delay, err)
+			delay = min(delay*2, maxReconnectDelay)
+			continue
+		}
+
+		s.mu.Lock()
+		s.status.Reconnects++
+		s.mu.Unlock()
+		log.Printf("RabbitMQ reconnected")
+
+		restoreConsumers()
+		return
+	}
+}
+
+// channel 返回当前可用的 Channel, 未连接时返回 nil
+func (s *supervisor) channel() *amqp.Channel {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !s.status.Connected {
+		return nil
+	}
+	return s.ch
+}
+
+// connection 返回当前可用的连接, 未连接时返回 nil
+func (s *supervisor) connection() *amqp.Connection {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !s.status.Connected {
+		return nil
+	}
+	return s.conn
+}
+
+// publisher 返回当前可用的发布 Channel, 未连接时返回 nil
+func (s *supervisor) publisher() *amqp.Channel {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !s.status.Connected {
+		return nil
+	}
+	return s.pub
+}
+
+// setError 标记为断开状态并记录错误
+func (s *supervisor) setError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status.Connected {
+		s.status.Since = time.Now()
+	}
+	s.status.Connected = false
+	s.status.LastError = err.Error()
+}
+
+func (s *supervisor) isClosing() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.closing
+}
+
+// close 停止重连并关闭连接
+func (s *supervisor) close() {
+	s.mu.Lock()
+	if s.closing {
+		s.mu.Unlock()
+		return
+	}
+	s.closing = true
+	close(s.done)
+	conn, ch, pub := s.conn, s.ch, s.pub
+	s.status.Connected = false
+	s.status.Since = time.Now()
+	s.mu.Unlock()
+
+	if pub != nil {
+		pub.Close()
+	}
+	if ch != nil {
+		ch.Close()
+	}
+	if conn != nil {
+		conn.Close()
+	}
+}
+
+// Health 返回连接健康状态
+func Health() Status {
+	if transport == nil {
+		return Status{}
+	}
+	return transport.Status()
+}
+
+// connection 返回当前可用的连接, 未初始化或未连接时返回 nil
+func connection() *amqp.Connection {
+	if sv == nil {
+		return nil
+	}
+	return sv.connection()
+}
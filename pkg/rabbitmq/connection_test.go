@@ -0,0 +1,73 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// useBroker 连接 RABBITMQ_TEST_URL 指定的 RabbitMQ, 未设置时跳过测试
+func useBroker(t *testing.T) {
+	t.Helper()
+
+	url := os.Getenv("RABBITMQ_TEST_URL")
+	if url == "" {
+		t.Skip("RABBITMQ_TEST_URL 未设置, 跳过需要 RabbitMQ 的测试")
+	}
+
+	consumerMu.Lock()
+	consumers = nil
+	shuttingDown = false
+	consumerMu.Unlock()
+
+	if err := NewRabbitmq(Config{URL: url}); err != nil {
+		t.Fatalf("NewRabbitmq: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := Shutdown(ctx); err != nil {
+			t.Errorf("Shutdown: %v", err)
+		}
+		setTransport(nil)
+	})
+}
+
+func TestPublishToMissingExchangeKeepsConsumers(t *testing.T) {
+	useBroker(t)
+
+	queue := fmt.Sprintf("test.pubclose.%d", time.Now().UnixNano())
+	RegisterQueue(QueueSpec{Name: queue, AutoDelete: true})
+
+	received := make(chan amqp.Delivery, 1)
+	Consume(queue, func(ctx context.Context, d amqp.Delivery) error {
+		received <- d
+		return nil
+	})
+
+	// 交换机不存在时 Broker 关闭发布 Channel, 只重新打开发布 Channel, 不断开连接
+	ctx := context.Background()
+	if err := PublishTo(ctx, "test.missing."+queue, "x", []byte("lost"), PublishOptions{}); err == nil {
+		t.Fatal("PublishTo an undeclared exchange succeeded")
+	}
+
+	waitFor(t, "publish channel reopened", func() bool {
+		return Publish(ctx, queue, []byte("after"), PublishOptions{}) == nil
+	})
+	select {
+	case d := <-received:
+		if string(d.Body) != "after" {
+			t.Fatalf("got body %q", d.Body)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("consumer detached after publish channel error")
+	}
+
+	if st := Health(); !st.Connected || st.Reconnects != 0 {
+		t.Fatalf("status = %+v, want connected without reconnects", st)
+	}
+}
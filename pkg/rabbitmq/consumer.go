@@ -0,0 +1,261 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// 默认重试策略
+var (
+	defaultMaxAttempts = 5
+	defaultRetryDelays = []time.Duration{5 * time.Second, 30 * time.Second, 2 * time.Minute, 10 * time.Minute}
+)
+
+// HandlerFunc 消息处理函数, 返回 nil 时确认消息, 返回错误时按重试策略处理 (Permanent 错误不重试)
+// ctx 在服务关闭超时后取消
+type HandlerFunc func(ctx context.Context, d amqp.Delivery) error
+
+// permanentError 不可重试的处理错误
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent 标记不可重试的错误 (如消息格式错误), 处理函数返回后消息直接进入死信队列
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// ConsumerOptions 消费者选项
+type ConsumerOptions struct {
+	MaxAttempts int             // 最大处理次数 (含首次), 超过后进入死信队列, 默认 5
+	RetryDelays []time.Duration // 第 N 次重试前的等待时间, 次数超出时使用最后一个值, 默认 5s/30s/2m/10m
+
+	Concurrency      int    // 并发处理的协程数, 默认 1
+	Prefetch         int    // 预取数量 (未确认消息上限), 默认与 Concurrency 相同
+	DedicatedChannel bool   // 使用独立的 Channel, 避免与其他队列共享 Channel
+	Exclusive        bool   // 独占消费, 同一队列只允许一个消费者
+	ConsumerTag      string // 消费者标签, 为空时自动生成
+
+	Middlewares []Middleware // 处理函数中间件, 按顺序包装 (如 Dedup)
+}
+
+// withDefaults 填充默认值
+func (opts ConsumerOptions) withDefaults() ConsumerOptions {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaultMaxAttempts
+	}
+	if opts.RetryDelays == nil {
+		opts.RetryDelays = defaultRetryDelays
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+	if opts.Prefetch <= 0 {
+		opts.Prefetch = opts.Concurrency
+	}
+	return opts
+}
+
+// consumer 通过 StartQueue/Consume 启动的消费者, 断线重连后自动重新订阅
+type consumer struct {
+	queue   string
+	handler HandlerFunc
+	opts    ConsumerOptions
+	tag     string
+	sub     Subscription // 当前订阅
+	stats   *queueStats
+}
+
+var (
+	consumerMu   sync.Mutex
+	consumers    []*consumer
+	consumerWg   sync.WaitGroup // 正在运行的消费协程
+	shuttingDown bool
+
+	// consumeCtx 传递给处理函数的 ctx, 关闭超时后取消
+	consumeCtx, cancelConsume = context.WithCancel(context.Background())
+)
+
+// StartQueue 启动队列监听，支持自定义队列名和处理函数
+// 处理失败的消息按 RetryDelays 延迟重试, 超过 MaxAttempts 后进入死信队列 <queue>.dlq
+// 未连接时先登记, 连接 (或重连) 成功后自动开始监听
+func StartQueue(queueName string, handler func([]byte) error, opts ...ConsumerOptions) {
+	Consume(queueName, func(ctx context.Context, d amqp.Delivery) error {
+		return handler(d.Body)
+	}, opts...)
+}
+
+// Consume 启动队列监听, 处理函数可以读取完整的消息属性 (消息头、消息 ID 等)
+func Consume(queueName string, handler HandlerFunc, opts ...ConsumerOptions) {
+	var o ConsumerOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	o = o.withDefaults()
+
+	tag := o.ConsumerTag
+	if tag == "" {
+		tag = fmt.Sprintf("%s-%d", queueName, time.Now().UnixNano())
+	}
+
+	c := &consumer{
+		queue:   queueName,
+		handler: chain(handler, o.Middlewares),
+		opts:    o,
+		tag:     tag,
+		stats:   statsFor(queueName),
+	}
+
+	consumerMu.Lock()
+	defer consumerMu.Unlock()
+	consumers = append(consumers, c)
+
+	if !Health().Connected {
+		log.Printf(" [*] RabbitMQ not connected, queue %s will be consumed after connecting", queueName)
+		return
+	}
+	if err := c.start(); err != nil {
+		failOnError(err, "Failed to start consumer")
+	}
+}
+
+// restoreConsumers 重连后重新声明队列并恢复所有消费者
+func restoreConsumers() {
+	consumerMu.Lock()
+	defer consumerMu.Unlock()
+	for _, c := range consumers {
+		if c.sub != nil && c.sub.Active() {
+			continue
+		}
+		if err := c.start(); err != nil {
+			failOnError(err, "Failed to restore consumer")
+		}
+	}
+}
+
+// start 声明队列并开始消费 (调用方需持有 consumerMu)
+func (c *consumer) start() error {
+	if transport == nil {
+		return ErrNotConnected
+	}
+
+	// 队列和重试拓扑在临时 Channel 上声明, 参数不一致时不影响共享的 Channel
+	if err := ensureQueue(c.queue); err != nil {
+		return err
+	}
+	err := transport.Declare(func(d Declarer) error {
+		return declareRetryTopology(d, c.queue, c.opts.RetryDelays)
+	})
+	if err != nil {
+		return err
+	}
+
+	sub, err := transport.Consume(c.queue, c.tag, c.opts)
+	if err != nil {
+		return err
+	}
+	c.sub = sub
+
+	for i := 0; i < c.opts.Concurrency; i++ {
+		consumerWg.Add(1)
+		go func() {
+			defer consumerWg.Done()
+			for d := range sub.Deliveries() {
+				c.handle(d)
+			}
+		}()
+	}
+	if closed := sub.Closed(); closed != nil {
+		go c.watchSubscription(sub, closed)
+	}
+
+	log.Printf(" [*] Listening on queue: %s (concurrency: %d, prefetch: %d)", c.queue, c.opts.Concurrency, c.opts.Prefetch)
+	return nil
+}
+
+// watchSubscription 独立 Channel 异常关闭而连接仍然存活时, 重新订阅
+// 连接断开的情况由 supervisor 重连后统一恢复
+func (c *consumer) watchSubscription(sub Subscription, closed <-chan *amqp.Error) {
+	reason, ok := <-closed
+	if !ok || reason == nil {
+		return
+	}
+	log.Printf("Consumer channel for %s closed: %s", c.queue, reason)
+
+	time.Sleep(minReconnectDelay)
+
+	consumerMu.Lock()
+	defer consumerMu.Unlock()
+	if shuttingDown || c.sub != sub || !Health().Connected {
+		return
+	}
+	if err := c.start(); err != nil {
+		failOnError(err, "Failed to restore consumer")
+	}
+}
+
+// handle 处理单条消息
+func (c *consumer) handle(d amqp.Delivery) {
+	log.Printf("Received a message from queue [%s]: %s", c.queue, d.Body)
+
+	c.stats.inFlight.Add(1)
+	defer c.stats.inFlight.Add(-1)
+
+	// 调用业务处理函数
+	if err := c.handler(withQueue(consumeCtx, c.queue), d); err != nil {
+		log.Printf("Error processing message: %v", err)
+		c.stats.failed.Add(1)
+		c.retry(d, err)
+		return
+	}
+
+	d.Ack(false) // 消息处理成功，确认
+	c.stats.processed.Add(1)
+	log.Printf("Message processed successfully")
+}
+
+// retry 处理失败: 未超过最大次数时投递到重试队列, 否则投递到死信队列
+// 投递成功后确认原消息; 投递失败时重新入队, 保证消息不丢失
+func (c *consumer) retry(d amqp.Delivery, cause error) {
+	attempts := retryCount(d) + 1
+
+	msg := redeliveryPublishing(d)
+	msg.Headers[retryCountHeader] = int32(attempts)
+	msg.Headers[lastErrorHeader] = cause.Error()
+
+	var permanent *permanentError
+	exchange, routingKey := "", c.queue
+	switch {
+	case errors.As(cause, &permanent):
+		exchange = DeadLetterExchange
+		log.Printf("Message failed permanently, dead-lettering to %s", deadLetterQueueName(c.queue))
+	case attempts >= c.opts.MaxAttempts:
+		exchange = DeadLetterExchange
+		log.Printf("Message exceeded max attempts (%d), dead-lettering to %s", c.opts.MaxAttempts, deadLetterQueueName(c.queue))
+	case len(c.opts.RetryDelays) > 0:
+		delay := c.opts.RetryDelays[min(attempts, len(c.opts.RetryDelays))-1]
+		routingKey = retryQueueName(c.queue, delay)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultPublishTimeout)
+	defer cancel()
+	if err := publish(ctx, exchange, routingKey, msg); err != nil {
+		log.Printf("Failed to redeliver message, requeue: %v", err)
+		d.Nack(false, true)
+		return
+	}
+	d.Ack(false)
+	if exchange == DeadLetterExchange {
+		c.stats.deadLettered.Add(1)
+	}
+}
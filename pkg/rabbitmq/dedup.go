@@ -0,0 +1,145 @@
+package rabbitmq
+
+import (
+	"container/list"
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// DedupStore 已处理消息记录
+type DedupStore interface {
+	// Seen 消息是否已处理 (且未过期)
+	Seen(ctx context.Context, key string) (bool, error)
+	// Mark 记录消息已处理, ttl 后过期
+	Mark(ctx context.Context, key string, ttl time.Duration) error
+}
+
+var (
+	dedupMu    sync.RWMutex
+	dedupStore DedupStore = NewMemoryDedupStore(defaultDedupCapacity)
+)
+
+// SetDedupStore 设置 Dedup 中间件使用的存储 (默认为进程内 LRU)
+func SetDedupStore(store DedupStore) {
+	dedupMu.Lock()
+	defer dedupMu.Unlock()
+	dedupStore = store
+}
+
+func currentDedupStore() DedupStore {
+	dedupMu.RLock()
+	defer dedupMu.RUnlock()
+	return dedupStore
+}
+
+// Dedup 消费去重中间件: 按 <队列>:<message-id> 记录处理成功的消息, ttl 内重复投递的消息直接确认, 不调用处理函数
+// 存储在处理时读取 SetDedupStore 的设置, 因此可以在 init 中注册 (此时数据库尚未连接)
+// 没有 message-id 的消息不去重; 存储不可用时记录日志并照常处理 (宁可重复, 不丢消息)
+func Dedup(ttl time.Duration) Middleware {
+	return DedupWithStore(nil, ttl)
+}
+
+// DedupWithStore 使用指定存储的去重中间件, store 为 nil 时使用 SetDedupStore 的设置
+func DedupWithStore(store DedupStore, ttl time.Duration) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, d amqp.Delivery) error {
+			if d.MessageId == "" {
+				return next(ctx, d)
+			}
+
+			s := store
+			if s == nil {
+				s = currentDedupStore()
+			}
+			key := QueueFromContext(ctx) + ":" + d.MessageId
+
+			seen, err := s.Seen(ctx, key)
+			if err != nil {
+				log.Printf("Dedup lookup failed for %s: %s", key, err)
+			} else if seen {
+				log.Printf("Skip duplicate message %s", key)
+				return nil
+			}
+
+			if err := next(ctx, d); err != nil {
+				return err
+			}
+
+			if err := s.Mark(ctx, key, ttl); err != nil {
+				log.Printf("Dedup mark failed for %s: %s", key, err)
+			}
+			return nil
+		}
+	}
+}
+
+// defaultDedupCapacity 进程内去重记录的默认容量
+const defaultDedupCapacity = 10000
+
+// MemoryDedupStore 进程内 LRU 去重存储 (不跨实例, 重启后丢失)
+type MemoryDedupStore struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // 最近使用的在前
+	entries  map[string]*list.Element
+}
+
+type dedupEntry struct {
+	key       string
+	expiresAt time.Time
+}
+
+// NewMemoryDedupStore 创建进程内去重存储, 超过 capacity 时淘汰最久未使用的记录
+func NewMemoryDedupStore(capacity int) *MemoryDedupStore {
+	if capacity <= 0 {
+		capacity = defaultDedupCapacity
+	}
+	return &MemoryDedupStore{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Seen 消息是否已处理
+func (s *MemoryDedupStore) Seen(ctx context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.entries[key]
+	if !ok {
+		return false, nil
+	}
+	if time.Now().After(el.Value.(*dedupEntry).expiresAt) {
+		s.order.Remove(el)
+		delete(s.entries, key)
+		return false, nil
+	}
+	s.order.MoveToFront(el)
+	return true, nil
+}
+
+// Mark 记录消息已处理
+func (s *MemoryDedupStore) Mark(ctx context.Context, key string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expiresAt := time.Now().Add(ttl)
+	if el, ok := s.entries[key]; ok {
+		el.Value.(*dedupEntry).expiresAt = expiresAt
+		s.order.MoveToFront(el)
+		return nil
+	}
+
+	s.entries[key] = s.order.PushFront(&dedupEntry{key: key, expiresAt: expiresAt})
+	for s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*dedupEntry).key)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// dedupCollection MongoDB 去重集合名称
+const dedupCollection = "processed_messages"
+
+// MongoDedupStore 基于 MongoDB 的去重存储, 过期记录由 TTL 索引自动删除
+type MongoDedupStore struct {
+	coll *mongo.Collection
+}
+
+// NewMongoDedupStore 创建 MongoDB 去重存储, 并创建 expires_at 的 TTL 索引
+func NewMongoDedupStore(ctx context.Context, db *mongo.Database) (*MongoDedupStore, error) {
+	coll := db.Collection(dedupCollection)
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建 %s TTL 索引失败: %w", dedupCollection, err)
+	}
+	return &MongoDedupStore{coll: coll}, nil
+}
+
+// Seen 消息是否已处理 (TTL 索引删除有延迟, 需要再比较过期时间)
+func (s *MongoDedupStore) Seen(ctx context.Context, key string) (bool, error) {
+	count, err := s.coll.CountDocuments(ctx, bson.M{
+		"_id":        key,
+		"expires_at": bson.M{"$gt": time.Now()},
+	})
+	return count > 0, err
+}
+
+// Mark 记录消息已处理
+func (s *MongoDedupStore) Mark(ctx context.Context, key string, ttl time.Duration) error {
+	now := time.Now()
+	_, err := s.coll.UpdateOne(ctx,
+		bson.M{"_id": key},
+		bson.M{"$set": bson.M{"expires_at": now.Add(ttl), "processed_at": now}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
@@ -0,0 +1,86 @@
+package rabbitmq
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/plugin/dbresolver"
+)
+
+// dedupCleanupInterval 清理过期去重记录的间隔
+const dedupCleanupInterval = 10 * time.Minute
+
+// ProcessedMessage 已处理消息记录 (processed_messages 表, 由 db/migrations 创建)
+// MessageKey 为去重键 (队列名称:消息 ID) 的 SHA-256, 队列名称最长 255 字节, 原始键可能超过索引长度限制
+type ProcessedMessage struct {
+	MessageKey  string    `gorm:"type:char(64);primaryKey"`
+	ExpiresAt   time.Time `gorm:"index"`
+	ProcessedAt time.Time
+}
+
+// TableName 表名
+func (ProcessedMessage) TableName() string {
+	return "processed_messages"
+}
+
+// GormDedupStore 基于 GORM 的去重存储, 多实例共享
+type GormDedupStore struct {
+	db *gorm.DB
+
+	mu          sync.Mutex
+	lastCleanup time.Time
+}
+
+// NewGormDedupStore 创建 GORM 去重存储
+func NewGormDedupStore(db *gorm.DB) *GormDedupStore {
+	return &GormDedupStore{db: db}
+}
+
+// Seen 消息是否已处理
+// 固定读主库: 只读副本存在复制延迟, 刚由其他实例处理的消息在副本上可能查不到, 导致重复消费
+func (s *GormDedupStore) Seen(ctx context.Context, key string) (bool, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Clauses(dbresolver.Write).Model(&ProcessedMessage{}).
+		Where("message_key = ? AND expires_at > ?", hashKey(key), time.Now()).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Mark 记录消息已处理, 并定期清理过期记录
+func (s *GormDedupStore) Mark(ctx context.Context, key string, ttl time.Duration) error {
+	now := time.Now()
+	record := ProcessedMessage{MessageKey: hashKey(key), ExpiresAt: now.Add(ttl), ProcessedAt: now}
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "message_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"expires_at", "processed_at"}),
+	}).Create(&record).Error
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	cleanup := now.Sub(s.lastCleanup) > dedupCleanupInterval
+	if cleanup {
+		s.lastCleanup = now
+	}
+	s.mu.Unlock()
+
+	if cleanup {
+		if err := s.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&ProcessedMessage{}).Error; err != nil {
+			log.Printf("Failed to clean up processed messages: %s", err)
+		}
+	}
+	return nil
+}
+
+// hashKey 去重键的 SHA-256 (十六进制, 固定 64 个字符)
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,44 @@
+package rabbitmq
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestGormDedupStoreLongKey(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&ProcessedMessage{}); err != nil {
+		t.Fatal(err)
+	}
+	s := NewGormDedupStore(db)
+	ctx := context.Background()
+
+	// 队列名称最长 255 字节, 加上消息 ID 后超过 varchar(191)
+	key := strings.Repeat("q", 255) + ":" + "3146e673-0f53-47c2-adb1-180446bac18c"
+	if seen, _ := s.Seen(ctx, key); seen {
+		t.Fatal("new key reported as seen")
+	}
+	if err := s.Mark(ctx, key, time.Minute); err != nil {
+		t.Fatalf("Mark: %v", err)
+	}
+	if seen, err := s.Seen(ctx, key); err != nil || !seen {
+		t.Fatalf("Seen after Mark = %v, %v", seen, err)
+	}
+	if seen, _ := s.Seen(ctx, key+"x"); seen {
+		t.Fatal("different key reported as seen")
+	}
+
+	var record ProcessedMessage
+	db.First(&record)
+	if len(record.MessageKey) != 64 {
+		t.Fatalf("stored key has %d characters, want 64", len(record.MessageKey))
+	}
+}
@@ -0,0 +1,94 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// 延迟队列的自动删除: 队列在 x-expires 时间内未被声明 (发布不算使用) 时由 Broker 删除
+// 每次发布时若距上次声明超过 delayQueueRedeclare 则重新声明, 因此只要
+// x-expires > delay + delayQueueRedeclare, 队列中最后一条消息到期前队列不会被删除
+const (
+	delayQueueRedeclare = 30 * time.Second
+	delayQueueIdle      = 2 * time.Minute // 最后一条消息到期后队列保留的时间
+)
+
+var (
+	delayMu       sync.Mutex
+	delayDeclared = make(map[string]time.Time) // 延迟队列最近一次声明的时间
+)
+
+// delayQueueName 延迟队列名称, 同一目标队列的同一延迟时间共用一个队列
+func delayQueueName(queue string, delay time.Duration) string {
+	return fmt.Sprintf("%s.delay.%dms", queue, delay.Milliseconds())
+}
+
+// PublishDelayed 发布延迟消息, delay 后投递到指定队列 (不需要 delayed-message 插件)
+//
+// 消息先进入没有消费者的延迟队列 <queue>.delay.<ms>ms, 队列 TTL 到期后经默认交换机回到目标队列.
+// 每个不同的 delay 对应一个延迟队列 (闲置后自动删除), 请使用少量固定的延迟时间, 不要按业务时间计算任意延迟.
+// opts.TTL 对延迟消息无效 (消息过期会提前投递)
+func PublishDelayed(ctx context.Context, queue string, body []byte, delay time.Duration, opts ...PublishOptions) error {
+	var o PublishOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	delay = delay.Truncate(time.Millisecond)
+	if delay <= 0 {
+		return Publish(ctx, queue, body, o)
+	}
+
+	if err := ensureQueue(queue); err != nil {
+		return err
+	}
+	name, err := ensureDelayQueue(queue, delay)
+	if err != nil {
+		return err
+	}
+
+	msg := o.publishing(body)
+	msg.Expiration = ""
+	return publish(ctx, "", name, msg)
+}
+
+// ensureDelayQueue 声明延迟队列, 距上次声明超过 delayQueueRedeclare 时重新声明以重置闲置计时
+func ensureDelayQueue(queue string, delay time.Duration) (string, error) {
+	if transport == nil {
+		return "", ErrNotConnected
+	}
+
+	name := delayQueueName(queue, delay)
+	delayMu.Lock()
+	last, ok := delayDeclared[name]
+	delayMu.Unlock()
+	if ok && time.Since(last) < delayQueueRedeclare {
+		return name, nil
+	}
+
+	spec := QueueSpec{
+		Name:                 name,
+		Durable:              true,
+		MessageTTL:           delay,
+		DeadLetterRoutingKey: queue,
+		Args: amqp.Table{
+			"x-dead-letter-exchange": "", // 默认交换机 (QueueSpec 不设置空的 DeadLetterExchange)
+			"x-expires":              (delay + delayQueueRedeclare + delayQueueIdle).Milliseconds(),
+		},
+	}
+	err := transport.Declare(func(d Declarer) error {
+		return declareQueueSpec(d, spec)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	delayMu.Lock()
+	delayDeclared[name] = time.Now()
+	delayMu.Unlock()
+	return name, nil
+}
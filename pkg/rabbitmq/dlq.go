@@ -0,0 +1,208 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// DeadLetterExchange 死信交换机, 各业务队列的死信队列以队列名为 routing key 绑定
+const DeadLetterExchange = "dlx"
+
+// 重试相关消息头
+const (
+	retryCountHeader = "x-retry-count" // 已重试次数
+	lastErrorHeader  = "x-last-error"  // 最近一次处理失败的原因
+)
+
+// retryQueueName 延迟重试队列名称, 同一延迟时间共用一个队列
+func retryQueueName(queue string, delay time.Duration) string {
+	return fmt.Sprintf("%s.retry.%dms", queue, delay.Milliseconds())
+}
+
+// deadLetterQueueName 死信队列名称
+func deadLetterQueueName(queue string) string {
+	return queue + ".dlq"
+}
+
+// declareRetryTopology 声明延迟重试队列和死信队列
+// 重试队列没有消费者, 消息 TTL 到期后经默认交换机回到业务队列
+func declareRetryTopology(d Declarer, queue string, delays []time.Duration) error {
+	for _, delay := range delays {
+		name := retryQueueName(queue, delay)
+		_, err := d.QueueDeclare(
+			name,  // name
+			true,  // durable
+			false, // delete when unused
+			false, // exclusive
+			false, // no-wait
+			amqp.Table{
+				"x-message-ttl":             delay.Milliseconds(),
+				"x-dead-letter-exchange":    "",
+				"x-dead-letter-routing-key": queue,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("declare retry queue %s: %w", name, err)
+		}
+	}
+
+	err := d.ExchangeDeclare(
+		DeadLetterExchange, // name
+		amqp.ExchangeDirect,
+		true,  // durable
+		false, // auto-deleted
+		false, // internal
+		false, // no-wait
+		nil,   // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("declare exchange %s: %w", DeadLetterExchange, err)
+	}
+
+	dlq := deadLetterQueueName(queue)
+	_, err = d.QueueDeclare(
+		dlq,   // name
+		true,  // durable
+		false, // delete when unused
+		false, // exclusive
+		false, // no-wait
+		nil,   // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("declare dead letter queue %s: %w", dlq, err)
+	}
+
+	if err := d.QueueBind(dlq, queue, DeadLetterExchange, false, nil); err != nil {
+		return fmt.Errorf("bind dead letter queue %s: %w", dlq, err)
+	}
+	return nil
+}
+
+// retryCount 读取消息已重试次数: 优先使用 x-retry-count, 其次使用 Broker 维护的 x-death
+func retryCount(d amqp.Delivery) int {
+	if n, ok := toInt(d.Headers[retryCountHeader]); ok {
+		return n
+	}
+
+	deaths, _ := d.Headers["x-death"].([]interface{})
+	total := 0
+	for _, death := range deaths {
+		if table, ok := death.(amqp.Table); ok {
+			if n, ok := toInt(table["count"]); ok {
+				total += n
+			}
+		}
+	}
+	return total
+}
+
+// toInt 转换消息头中的整数值 (不同客户端可能编码为不同的整数类型)
+func toInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int8:
+		return int(n), true
+	case int16:
+		return int(n), true
+	case int32:
+		return int(n), true
+	case int64:
+		return int(n), true
+	case uint8:
+		return int(n), true
+	case uint16:
+		return int(n), true
+	case uint32:
+		return int(n), true
+	default:
+		return 0, false
+	}
+}
+
+// redeliveryPublishing 复制原消息属性, 用于重新投递
+// 不复制 Expiration (避免在重试队列中过期) 和 UserId (与当前连接用户不一致时会被拒绝)
+func redeliveryPublishing(d amqp.Delivery) amqp.Publishing {
+	headers := make(amqp.Table, len(d.Headers)+2)
+	for k, v := range d.Headers {
+		headers[k] = v
+	}
+
+	return amqp.Publishing{
+		Headers:         headers,
+		ContentType:     d.ContentType,
+		ContentEncoding: d.ContentEncoding,
+		DeliveryMode:    d.DeliveryMode,
+		Priority:        d.Priority,
+		CorrelationId:   d.CorrelationId,
+		ReplyTo:         d.ReplyTo,
+		MessageId:       d.MessageId,
+		Timestamp:       d.Timestamp,
+		Type:            d.Type,
+		AppId:           d.AppId,
+		Body:            d.Body,
+	}
+}
+
+// ReplayDeadLetters 将死信队列中的消息移回业务队列, 并清除重试次数
+// limit 为 0 时移动调用时刻死信队列中的全部消息, 返回成功移动的条数
+func ReplayDeadLetters(ctx context.Context, queue string, limit int) (int, error) {
+	if transport == nil {
+		return 0, ErrNotConnected
+	}
+
+	if err := ensureQueue(queue); err != nil {
+		return 0, err
+	}
+	err := transport.Declare(func(d Declarer) error {
+		return declareRetryTopology(d, queue, nil)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	dlq := deadLetterQueueName(queue)
+	total, err := transport.Inspect(dlq)
+	if err != nil {
+		return 0, err
+	}
+
+	// 只处理当前已有的消息, 避免重放后再次失败的消息被循环处理
+	if limit > 0 && limit < total {
+		total = limit
+	}
+
+	moved := 0
+	for moved < total {
+		if err := ctx.Err(); err != nil {
+			return moved, err
+		}
+
+		d, ok, err := transport.Get(dlq)
+		if err != nil {
+			return moved, fmt.Errorf("get from %s: %w", dlq, err)
+		}
+		if !ok {
+			break
+		}
+
+		msg := redeliveryPublishing(d)
+		delete(msg.Headers, retryCountHeader)
+		delete(msg.Headers, lastErrorHeader)
+		delete(msg.Headers, "x-death")
+
+		if err := publish(ctx, "", queue, msg); err != nil {
+			d.Nack(false, true)
+			return moved, err
+		}
+		if err := d.Ack(false); err != nil {
+			log.Printf("Failed to ack dead letter message: %s", err)
+		}
+		moved++
+	}
+	return moved, nil
+}
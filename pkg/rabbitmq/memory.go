@@ -0,0 +1,652 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// memoryTransport 进程内消息总线, 模拟 RabbitMQ 中本包用到的语义:
+// 默认/direct/fanout/topic 交换机, 预取与确认 (ack / nack / requeue), 队列和消息 TTL,
+// 死信交换机 (x-dead-letter-*), 队列长度限制 (x-max-length / x-overflow) 和 direct reply-to
+// 消息只保存在内存中, 不持久化, 不跨进程
+type memoryTransport struct {
+	mu        sync.Mutex
+	queues    map[string]*memQueue
+	exchanges map[string]*memExchange
+	nextTag   uint64
+	since     time.Time
+	closed    bool
+}
+
+// memExchange 交换机及其绑定
+type memExchange struct {
+	kind     string
+	bindings []memBinding
+}
+
+type memBinding struct {
+	queue string
+	key   string
+	args  amqp.Table
+}
+
+// memQueue 队列
+type memQueue struct {
+	name       string
+	durable    bool
+	autoDelete bool
+	exclusive  bool
+	args       amqp.Table
+
+	ready     []*memMessage
+	consumers []*memConsumer
+	unacked   map[uint64]*memMessage
+	next      int // 轮询投递的下一个消费者
+}
+
+// memMessage 队列中的消息
+type memMessage struct {
+	msg         amqp.Publishing
+	exchange    string
+	routingKey  string
+	redelivered bool
+	expiresAt   time.Time // 零值表示不过期
+	consumer    *memConsumer
+}
+
+// memConsumer 队列订阅
+type memConsumer struct {
+	t        *memoryTransport
+	queue    *memQueue
+	tag      string
+	prefetch int
+	inFlight int
+	msgs     chan amqp.Delivery
+	active   bool
+}
+
+// newMemoryTransport 创建进程内消息总线
+func newMemoryTransport() *memoryTransport {
+	return &memoryTransport{
+		queues:    make(map[string]*memQueue),
+		exchanges: make(map[string]*memExchange),
+		since:     time.Now(),
+	}
+}
+
+func (t *memoryTransport) Status() Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return Status{Enabled: true, Connected: !t.closed, Transport: TransportMemory, Since: t.since}
+}
+
+func (t *memoryTransport) Declare(fn func(d Declarer) error) error {
+	if t.isClosed() {
+		return ErrNotConnected
+	}
+	return fn(t)
+}
+
+// QueueDeclare 声明队列, 参数与已存在的队列不一致时返回 PRECONDITION_FAILED
+func (t *memoryTransport) QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if q, ok := t.queues[name]; ok {
+		if q.durable != durable || q.autoDelete != autoDelete || q.exclusive != exclusive || !sameArgs(q.args, args) {
+			return amqp.Queue{}, &amqp.Error{
+				Code:   amqp.PreconditionFailed,
+				Reason: fmt.Sprintf("PRECONDITION_FAILED - inequivalent arg for queue '%s'", name),
+			}
+		}
+		return amqp.Queue{Name: name, Messages: len(q.ready), Consumers: len(q.consumers)}, nil
+	}
+
+	t.queues[name] = &memQueue{
+		name:       name,
+		durable:    durable,
+		autoDelete: autoDelete,
+		exclusive:  exclusive,
+		args:       args,
+		unacked:    make(map[uint64]*memMessage),
+	}
+	return amqp.Queue{Name: name}, nil
+}
+
+// ExchangeDeclare 声明交换机
+func (t *memoryTransport) ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if ex, ok := t.exchanges[name]; ok {
+		if ex.kind != kind {
+			return &amqp.Error{
+				Code:   amqp.PreconditionFailed,
+				Reason: fmt.Sprintf("PRECONDITION_FAILED - inequivalent arg 'type' for exchange '%s'", name),
+			}
+		}
+		return nil
+	}
+	switch kind {
+	case amqp.ExchangeDirect, amqp.ExchangeFanout, amqp.ExchangeTopic, amqp.ExchangeHeaders:
+	default:
+		return &amqp.Error{Code: amqp.CommandInvalid, Reason: "COMMAND_INVALID - unknown exchange type '" + kind + "'"}
+	}
+	t.exchanges[name] = &memExchange{kind: kind}
+	return nil
+}
+
+// QueueBind 绑定队列到交换机
+func (t *memoryTransport) QueueBind(name, key, exchange string, noWait bool, args amqp.Table) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ex, ok := t.exchanges[exchange]
+	if !ok {
+		return notFound("exchange", exchange)
+	}
+	if _, ok := t.queues[name]; !ok {
+		return notFound("queue", name)
+	}
+	for _, b := range ex.bindings {
+		if b.queue == name && b.key == key && sameArgs(b.args, args) {
+			return nil
+		}
+	}
+	ex.bindings = append(ex.bindings, memBinding{queue: name, key: key, args: args})
+	return nil
+}
+
+// Publish 路由并投递消息; 无法路由的消息被丢弃 (与 RabbitMQ 非 mandatory 发布一致)
+func (t *memoryTransport) Publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// direct reply-to: 回复直接交给等待中的 Call
+	if exchange == "" && strings.HasPrefix(routingKey, directReplyTo) {
+		rpc.deliverReply(memoryDelivery(nil, 0, &memMessage{msg: msg, routingKey: routingKey}))
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return ErrNotConnected
+	}
+	return t.route(exchange, routingKey, msg)
+}
+
+// route 按交换机类型将消息投递到匹配的队列 (调用方需持有 mu)
+func (t *memoryTransport) route(exchange, routingKey string, msg amqp.Publishing) error {
+	if exchange == "" {
+		if q, ok := t.queues[routingKey]; ok {
+			return t.enqueue(q, &memMessage{msg: msg, exchange: exchange, routingKey: routingKey})
+		}
+		return nil
+	}
+
+	ex, ok := t.exchanges[exchange]
+	if !ok {
+		return notFound("exchange", exchange)
+	}
+	routed := make(map[string]bool)
+	var nacked error
+	for _, b := range ex.bindings {
+		if routed[b.queue] || !ex.matches(b, routingKey, msg.Headers) {
+			continue
+		}
+		routed[b.queue] = true
+		if q, ok := t.queues[b.queue]; ok {
+			if err := t.enqueue(q, &memMessage{msg: copyPublishing(msg), exchange: exchange, routingKey: routingKey}); err != nil {
+				nacked = err
+			}
+		}
+	}
+	return nacked
+}
+
+// matches 绑定是否匹配路由键 (或消息头)
+func (ex *memExchange) matches(b memBinding, routingKey string, headers amqp.Table) bool {
+	switch ex.kind {
+	case amqp.ExchangeFanout:
+		return true
+	case amqp.ExchangeTopic:
+		return topicMatch(strings.Split(b.key, "."), strings.Split(routingKey, "."))
+	case amqp.ExchangeHeaders:
+		matchAll := b.args["x-match"] != "any"
+		matched := 0
+		total := 0
+		for k, v := range b.args {
+			if strings.HasPrefix(k, "x-") {
+				continue
+			}
+			total++
+			if hv, ok := headers[k]; ok && reflect.DeepEqual(hv, v) {
+				matched++
+			}
+		}
+		if matchAll {
+			return matched == total
+		}
+		return matched > 0
+	default:
+		return b.key == routingKey
+	}
+}
+
+// topicMatch topic 交换机匹配: * 匹配一个单词, # 匹配零个或多个单词
+func topicMatch(pattern, words []string) bool {
+	if len(pattern) == 0 {
+		return len(words) == 0
+	}
+	switch pattern[0] {
+	case "#":
+		for i := 0; i <= len(words); i++ {
+			if topicMatch(pattern[1:], words[i:]) {
+				return true
+			}
+		}
+		return false
+	case "*":
+		return len(words) > 0 && topicMatch(pattern[1:], words[1:])
+	default:
+		return len(words) > 0 && pattern[0] == words[0] && topicMatch(pattern[1:], words[1:])
+	}
+}
+
+// enqueue 消息入队, 处理长度限制和过期时间, 然后尝试投递 (调用方需持有 mu)
+func (t *memoryTransport) enqueue(q *memQueue, m *memMessage) error {
+	if max, ok := toInt(q.args["x-max-length"]); ok && len(q.ready) >= max {
+		switch q.args["x-overflow"] {
+		case OverflowRejectPublish, OverflowRejectPublishDLX:
+			if q.args["x-overflow"] == OverflowRejectPublishDLX {
+				t.deadLetter(q, m, "rejected")
+			}
+			return fmt.Errorf("publish to %s: %w", q.name, ErrNacked)
+		default:
+			if len(q.ready) > 0 {
+				head := q.ready[0]
+				q.ready = q.ready[1:]
+				t.deadLetter(q, head, "maxlen")
+			}
+		}
+	}
+
+	if ttl, ok := messageTTL(q, m.msg); ok {
+		m.expiresAt = time.Now().Add(ttl)
+		time.AfterFunc(ttl, func() { t.expire(q, m) })
+	}
+	q.ready = append(q.ready, m)
+	t.dispatch(q)
+	return nil
+}
+
+// messageTTL 消息的过期时间: 队列 x-message-ttl 和消息 Expiration 中较小的一个
+func messageTTL(q *memQueue, msg amqp.Publishing) (time.Duration, bool) {
+	var ttl time.Duration
+	found := false
+	if ms, ok := toInt64(q.args["x-message-ttl"]); ok {
+		ttl, found = time.Duration(ms)*time.Millisecond, true
+	}
+	if msg.Expiration != "" {
+		if ms, err := strconv.ParseInt(msg.Expiration, 10, 64); err == nil {
+			d := time.Duration(ms) * time.Millisecond
+			if !found || d < ttl {
+				ttl, found = d, true
+			}
+		}
+	}
+	return ttl, found
+}
+
+// expire 消息过期: 仍在队列中等待投递时移出并转入死信交换机
+func (t *memoryTransport) expire(q *memQueue, m *memMessage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, ready := range q.ready {
+		if ready == m {
+			q.ready = append(q.ready[:i], q.ready[i+1:]...)
+			t.deadLetter(q, m, "expired")
+			return
+		}
+	}
+}
+
+// deadLetter 按队列的 x-dead-letter-* 参数转发消息, 未设置死信交换机时丢弃 (调用方需持有 mu)
+func (t *memoryTransport) deadLetter(q *memQueue, m *memMessage, reason string) {
+	dlx, ok := q.args["x-dead-letter-exchange"].(string)
+	if !ok {
+		return
+	}
+	routingKey := m.routingKey
+	if key, ok := q.args["x-dead-letter-routing-key"].(string); ok {
+		routingKey = key
+	}
+
+	msg := copyPublishing(m.msg)
+	msg.Expiration = ""
+	msg.Headers["x-death"] = addDeath(msg.Headers["x-death"], q.name, reason, m.exchange, m.routingKey)
+
+	// 在新的调用栈中投递, 避免 TTL 为 0 的队列之间形成递归
+	go func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if !t.closed {
+			t.route(dlx, routingKey, msg)
+		}
+	}()
+}
+
+// addDeath 更新 x-death 消息头 (同一队列和原因的记录累加 count)
+func addDeath(existing any, queue, reason, exchange, routingKey string) []any {
+	deaths, _ := existing.([]any)
+	for _, d := range deaths {
+		if table, ok := d.(amqp.Table); ok && table["queue"] == queue && table["reason"] == reason {
+			count, _ := toInt64(table["count"])
+			table["count"] = count + 1
+			return deaths
+		}
+	}
+	return append([]any{amqp.Table{
+		"count":        int64(1),
+		"reason":       reason,
+		"queue":        queue,
+		"exchange":     exchange,
+		"routing-keys": []any{routingKey},
+		"time":         time.Now(),
+	}}, deaths...)
+}
+
+// dispatch 将等待中的消息轮询投递给未达到预取上限的消费者 (调用方需持有 mu)
+func (t *memoryTransport) dispatch(q *memQueue) {
+	for len(q.ready) > 0 && len(q.consumers) > 0 {
+		var c *memConsumer
+		for i := 0; i < len(q.consumers); i++ {
+			candidate := q.consumers[(q.next+i)%len(q.consumers)]
+			if candidate.prefetch <= 0 || candidate.inFlight < candidate.prefetch {
+				c = candidate
+				q.next = (q.next + i + 1) % len(q.consumers)
+				break
+			}
+		}
+		if c == nil {
+			return
+		}
+
+		m := q.ready[0]
+		q.ready = q.ready[1:]
+
+		t.nextTag++
+		m.consumer = c
+		q.unacked[t.nextTag] = m
+		c.inFlight++
+		// msgs 的容量等于预取数量, inFlight 未达到上限时不会阻塞
+		c.msgs <- memoryDelivery(&memAcknowledger{t: t, q: q}, t.nextTag, m)
+	}
+}
+
+// memoryDelivery 构造投递给消费者的消息
+func memoryDelivery(ack amqp.Acknowledger, tag uint64, m *memMessage) amqp.Delivery {
+	msg := m.msg
+	d := amqp.Delivery{
+		Acknowledger:    ack,
+		Headers:         msg.Headers,
+		ContentType:     msg.ContentType,
+		ContentEncoding: msg.ContentEncoding,
+		DeliveryMode:    msg.DeliveryMode,
+		Priority:        msg.Priority,
+		CorrelationId:   msg.CorrelationId,
+		ReplyTo:         msg.ReplyTo,
+		Expiration:      msg.Expiration,
+		MessageId:       msg.MessageId,
+		Timestamp:       msg.Timestamp,
+		Type:            msg.Type,
+		UserId:          msg.UserId,
+		AppId:           msg.AppId,
+		DeliveryTag:     tag,
+		Redelivered:     m.redelivered,
+		Exchange:        m.exchange,
+		RoutingKey:      m.routingKey,
+		Body:            msg.Body,
+	}
+	if m.consumer != nil {
+		d.ConsumerTag = m.consumer.tag
+	}
+	return d
+}
+
+// Consume 订阅队列 (队列需要已声明)
+func (t *memoryTransport) Consume(queue, tag string, opts ConsumerOptions) (Subscription, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return nil, ErrNotConnected
+	}
+	q, ok := t.queues[queue]
+	if !ok {
+		return nil, notFound("queue", queue)
+	}
+	if opts.Exclusive && len(q.consumers) > 0 {
+		return nil, &amqp.Error{Code: amqp.AccessRefused, Reason: "ACCESS_REFUSED - queue '" + queue + "' in exclusive use"}
+	}
+
+	prefetch := opts.Prefetch
+	if prefetch <= 0 {
+		prefetch = 1
+	}
+	c := &memConsumer{
+		t:        t,
+		queue:    q,
+		tag:      tag,
+		prefetch: prefetch,
+		msgs:     make(chan amqp.Delivery, prefetch),
+		active:   true,
+	}
+	q.consumers = append(q.consumers, c)
+	t.dispatch(q)
+	return c, nil
+}
+
+func (c *memConsumer) Deliveries() <-chan amqp.Delivery { return c.msgs }
+func (c *memConsumer) Closed() <-chan *amqp.Error       { return nil }
+
+func (c *memConsumer) Active() bool {
+	c.t.mu.Lock()
+	defer c.t.mu.Unlock()
+	return c.active
+}
+
+// Cancel 取消订阅, 已投递到 Channel 中的消息仍会被读取和确认
+func (c *memConsumer) Cancel() error {
+	c.t.mu.Lock()
+	defer c.t.mu.Unlock()
+
+	if !c.active {
+		return nil
+	}
+	c.active = false
+	for i, existing := range c.queue.consumers {
+		if existing == c {
+			c.queue.consumers = append(c.queue.consumers[:i], c.queue.consumers[i+1:]...)
+			break
+		}
+	}
+	close(c.msgs)
+	return nil
+}
+
+// Get 拉取一条消息
+func (t *memoryTransport) Get(queue string) (amqp.Delivery, bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	q, ok := t.queues[queue]
+	if !ok {
+		return amqp.Delivery{}, false, notFound("queue", queue)
+	}
+	if len(q.ready) == 0 {
+		return amqp.Delivery{}, false, nil
+	}
+
+	m := q.ready[0]
+	q.ready = q.ready[1:]
+	m.consumer = nil
+	t.nextTag++
+	q.unacked[t.nextTag] = m
+	return memoryDelivery(&memAcknowledger{t: t, q: q}, t.nextTag, m), true, nil
+}
+
+// Inspect 队列中等待投递的消息数
+func (t *memoryTransport) Inspect(queue string) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	q, ok := t.queues[queue]
+	if !ok {
+		return 0, notFound("queue", queue)
+	}
+	return len(q.ready), nil
+}
+
+// Close 关闭消息总线, 取消所有订阅并丢弃所有消息
+func (t *memoryTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+	for _, q := range t.queues {
+		for _, c := range q.consumers {
+			if c.active {
+				c.active = false
+				close(c.msgs)
+			}
+		}
+		q.consumers = nil
+	}
+	return nil
+}
+
+func (t *memoryTransport) isClosed() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.closed
+}
+
+// memAcknowledger 实现 amqp.Acknowledger, 确认或拒绝队列中未确认的消息
+type memAcknowledger struct {
+	t *memoryTransport
+	q *memQueue
+}
+
+func (a *memAcknowledger) Ack(tag uint64, multiple bool) error {
+	return a.settle(tag, multiple, func(m *memMessage) {})
+}
+
+func (a *memAcknowledger) Nack(tag uint64, multiple, requeue bool) error {
+	return a.settle(tag, multiple, func(m *memMessage) {
+		if requeue {
+			m.redelivered = true
+			a.q.ready = append([]*memMessage{m}, a.q.ready...)
+			return
+		}
+		a.t.deadLetter(a.q, m, "rejected")
+	})
+}
+
+func (a *memAcknowledger) Reject(tag uint64, requeue bool) error {
+	return a.Nack(tag, false, requeue)
+}
+
+// settle 结束未确认的消息 (multiple 时包括同一消费者之前的所有消息), 然后继续投递
+func (a *memAcknowledger) settle(tag uint64, multiple bool, fn func(m *memMessage)) error {
+	a.t.mu.Lock()
+	defer a.t.mu.Unlock()
+
+	m, ok := a.q.unacked[tag]
+	if !ok {
+		return &amqp.Error{Code: amqp.PreconditionFailed, Reason: fmt.Sprintf("PRECONDITION_FAILED - unknown delivery tag %d", tag)}
+	}
+
+	tags := []uint64{tag}
+	if multiple {
+		tags = tags[:0]
+		for t, other := range a.q.unacked {
+			if t <= tag && other.consumer == m.consumer {
+				tags = append(tags, t)
+			}
+		}
+	}
+	for _, t := range tags {
+		settled := a.q.unacked[t]
+		delete(a.q.unacked, t)
+		if settled.consumer != nil {
+			settled.consumer.inFlight--
+			settled.consumer = nil
+		}
+		fn(settled)
+	}
+	a.t.dispatch(a.q)
+	return nil
+}
+
+// copyPublishing 复制消息 (消息头单独复制, 避免多个队列共享同一个 map)
+func copyPublishing(msg amqp.Publishing) amqp.Publishing {
+	headers := make(amqp.Table, len(msg.Headers)+1)
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	msg.Headers = headers
+	return msg
+}
+
+// sameArgs 比较声明参数 (nil 与空表视为相同, 整数按值比较)
+func sameArgs(a, b amqp.Table) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, av := range a {
+		bv, ok := b[k]
+		if !ok {
+			return false
+		}
+		if ai, ok := toInt64(av); ok {
+			if bi, ok := toInt64(bv); ok && ai == bi {
+				continue
+			}
+			return false
+		}
+		if !reflect.DeepEqual(av, bv) {
+			return false
+		}
+	}
+	return true
+}
+
+// toInt64 转换整数类型的参数值
+func toInt64(v any) (int64, bool) {
+	if n, ok := v.(int64); ok {
+		return n, true
+	}
+	if n, ok := toInt(v); ok {
+		return int64(n), true
+	}
+	return 0, false
+}
+
+func notFound(kind, name string) error {
+	return &amqp.Error{Code: amqp.NotFound, Reason: fmt.Sprintf("NOT_FOUND - no %s '%s'", kind, name)}
+}
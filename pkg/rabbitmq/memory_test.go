@@ -0,0 +1,440 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// useMemory 切换到新的进程内消息总线, 测试结束时关闭
+// 队列名称在各测试中不重复, 因为消费者统计和拓扑注册是包级状态
+func useMemory(t *testing.T) {
+	t.Helper()
+
+	consumerMu.Lock()
+	consumers = nil
+	shuttingDown = false
+	consumerMu.Unlock()
+
+	registryMu.Lock()
+	registeredConsumers = nil
+	consumersStarted = false
+	registryMu.Unlock()
+
+	if err := NewRabbitmq(Config{Transport: TransportMemory}); err != nil {
+		t.Fatalf("NewRabbitmq: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := Shutdown(ctx); err != nil {
+			t.Errorf("Shutdown: %v", err)
+		}
+	})
+}
+
+// waitFor 等待条件成立, 超时时测试失败
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// queueLen 队列中等待投递的消息数
+func queueLen(t *testing.T, queue string) int {
+	t.Helper()
+
+	n, err := transport.Inspect(queue)
+	if err != nil {
+		t.Fatalf("Inspect %s: %v", queue, err)
+	}
+	return n
+}
+
+func TestMemoryPublishConsume(t *testing.T) {
+	useMemory(t)
+
+	received := make(chan amqp.Delivery, 1)
+	Consume("test.basic", func(ctx context.Context, d amqp.Delivery) error {
+		if q := QueueFromContext(ctx); q != "test.basic" {
+			t.Errorf("QueueFromContext = %q", q)
+		}
+		received <- d
+		return nil
+	})
+
+	err := Publish(context.Background(), "test.basic", []byte("hello"), PublishOptions{MessageID: "m-1"})
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	select {
+	case d := <-received:
+		if string(d.Body) != "hello" || d.MessageId != "m-1" {
+			t.Fatalf("got body %q, message id %q", d.Body, d.MessageId)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("message not delivered")
+	}
+}
+
+func TestMemoryRetryDeadLetterReplay(t *testing.T) {
+	useMemory(t)
+
+	const queue = "test.retry"
+	var (
+		attempts atomic.Int32
+		succeed  atomic.Bool
+		mu       sync.Mutex
+		deaths   []any
+	)
+	Consume(queue, func(ctx context.Context, d amqp.Delivery) error {
+		attempts.Add(1)
+		if d, ok := d.Headers["x-death"].([]any); ok {
+			mu.Lock()
+			deaths = d
+			mu.Unlock()
+		}
+		if succeed.Load() {
+			return nil
+		}
+		return errors.New("boom")
+	}, ConsumerOptions{MaxAttempts: 3, RetryDelays: []time.Duration{10 * time.Millisecond}})
+
+	if err := Publish(context.Background(), queue, []byte("job"), PublishOptions{}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	dlq := deadLetterQueueName(queue)
+	waitFor(t, "message in dead letter queue", func() bool { return queueLen(t, dlq) == 1 })
+	if n := attempts.Load(); n != 3 {
+		t.Fatalf("attempts = %d, want 3", n)
+	}
+
+	// 重试经过 TTL 队列的死信转发, 消息头中应有 x-death 记录
+	mu.Lock()
+	if len(deaths) == 0 {
+		t.Error("retried message has no x-death header")
+	} else if death, _ := deaths[0].(amqp.Table); death["reason"] != "expired" {
+		t.Errorf("x-death reason = %v, want expired", death["reason"])
+	}
+	mu.Unlock()
+
+	d, ok, err := transport.Get(dlq)
+	if err != nil || !ok {
+		t.Fatalf("Get %s: ok=%v err=%v", dlq, ok, err)
+	}
+	if n, _ := toInt(d.Headers[retryCountHeader]); n != 3 {
+		t.Errorf("%s = %v, want 3", retryCountHeader, d.Headers[retryCountHeader])
+	}
+	if d.Headers[lastErrorHeader] != "boom" {
+		t.Errorf("%s = %v, want boom", lastErrorHeader, d.Headers[lastErrorHeader])
+	}
+	d.Nack(false, true)
+
+	succeed.Store(true)
+	moved, err := ReplayDeadLetters(context.Background(), queue, 0)
+	if err != nil {
+		t.Fatalf("ReplayDeadLetters: %v", err)
+	}
+	if moved != 1 {
+		t.Fatalf("ReplayDeadLetters moved %d, want 1", moved)
+	}
+	waitFor(t, "replayed message processed", func() bool { return attempts.Load() == 4 })
+	if n := queueLen(t, dlq); n != 0 {
+		t.Fatalf("dead letter queue has %d messages after replay", n)
+	}
+}
+
+func TestMemoryPermanentError(t *testing.T) {
+	useMemory(t)
+
+	const queue = "test.permanent"
+	var attempts atomic.Int32
+	Consume(queue, func(ctx context.Context, d amqp.Delivery) error {
+		attempts.Add(1)
+		return Permanent(errors.New("bad payload"))
+	}, ConsumerOptions{RetryDelays: []time.Duration{10 * time.Millisecond}})
+
+	if err := Publish(context.Background(), queue, []byte("{"), PublishOptions{}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	waitFor(t, "message in dead letter queue", func() bool { return queueLen(t, deadLetterQueueName(queue)) == 1 })
+	if n := attempts.Load(); n != 1 {
+		t.Fatalf("attempts = %d, want 1", n)
+	}
+}
+
+func TestMemoryPublishDelayed(t *testing.T) {
+	useMemory(t)
+
+	const queue = "test.delayed"
+	const delay = 100 * time.Millisecond
+	received := make(chan time.Time, 1)
+	Consume(queue, func(ctx context.Context, d amqp.Delivery) error {
+		received <- time.Now()
+		return nil
+	})
+
+	start := time.Now()
+	if err := PublishDelayed(context.Background(), queue, []byte("later"), delay); err != nil {
+		t.Fatalf("PublishDelayed: %v", err)
+	}
+	if n := queueLen(t, delayQueueName(queue, delay)); n != 1 {
+		t.Fatalf("delay queue has %d messages, want 1", n)
+	}
+
+	select {
+	case at := <-received:
+		if elapsed := at.Sub(start); elapsed < delay {
+			t.Fatalf("delivered after %s, want at least %s", elapsed, delay)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("delayed message not delivered")
+	}
+}
+
+func TestMemoryCallServe(t *testing.T) {
+	useMemory(t)
+
+	const queue = "test.rpc"
+	Serve(queue, func(ctx context.Context, req []byte) ([]byte, error) {
+		if string(req) == "fail" {
+			return nil, errors.New("cannot do that")
+		}
+		return append([]byte("echo:"), req...), nil
+	})
+	StartConsumers()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := Call(ctx, queue, []byte("ping"))
+	if err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+	if string(resp) != "echo:ping" {
+		t.Fatalf("Call = %q, want echo:ping", resp)
+	}
+
+	_, err = Call(ctx, queue, []byte("fail"))
+	var remote *RemoteError
+	if !errors.As(err, &remote) || remote.Message != "cannot do that" {
+		t.Fatalf("Call error = %v, want RemoteError", err)
+	}
+}
+
+func TestMemoryCallTimeout(t *testing.T) {
+	useMemory(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := Call(ctx, "test.rpc.nobody", []byte("ping"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Call error = %v, want deadline exceeded", err)
+	}
+}
+
+func TestMemoryExchangeRouting(t *testing.T) {
+	// 与实际启动顺序相同: 在 init 中注册拓扑, 初始化传输层时自动声明
+	RegisterExchange(Exchange{Name: "test.topic", Kind: amqp.ExchangeTopic, Durable: true})
+	RegisterBinding(Binding{Queue: "test.topic.one", Exchange: "test.topic", RoutingKey: "order.*"})
+	RegisterBinding(Binding{Queue: "test.topic.all", Exchange: "test.topic", RoutingKey: "order.#"})
+	RegisterExchange(Exchange{Name: "test.fanout", Kind: amqp.ExchangeFanout, Durable: true})
+	RegisterBinding(Binding{Queue: "test.fanout.a", Exchange: "test.fanout"})
+	RegisterBinding(Binding{Queue: "test.fanout.b", Exchange: "test.fanout"})
+	RegisterExchange(Exchange{Name: "test.headers", Kind: amqp.ExchangeHeaders, Durable: true})
+	RegisterBinding(Binding{Queue: "test.headers.pdf", Exchange: "test.headers", Args: amqp.Table{"x-match": "all", "format": "pdf", "type": "report"}})
+	RegisterBinding(Binding{Queue: "test.headers.any", Exchange: "test.headers", Args: amqp.Table{"x-match": "any", "format": "pdf", "type": "report"}})
+	useMemory(t)
+
+	ctx := context.Background()
+	publishTo := func(exchange, key string, headers amqp.Table) {
+		t.Helper()
+		if err := PublishTo(ctx, exchange, key, []byte(key), PublishOptions{Headers: headers}); err != nil {
+			t.Fatalf("PublishTo %s %s: %v", exchange, key, err)
+		}
+	}
+	publishTo("test.topic", "order.created", nil)
+	publishTo("test.topic", "order.created.eu", nil)
+	publishTo("test.topic", "user.created", nil)
+	publishTo("test.fanout", "ignored", nil)
+	publishTo("test.headers", "", amqp.Table{"format": "pdf", "type": "report"})
+	publishTo("test.headers", "", amqp.Table{"format": "pdf", "type": "log"})
+
+	want := map[string]int{
+		"test.topic.one":   1,
+		"test.topic.all":   2,
+		"test.fanout.a":    1,
+		"test.fanout.b":    1,
+		"test.headers.pdf": 1,
+		"test.headers.any": 2,
+	}
+	for queue, n := range want {
+		if got := queueLen(t, queue); got != n {
+			t.Errorf("%s has %d messages, want %d", queue, got, n)
+		}
+	}
+
+	if err := PublishTo(ctx, "test.missing", "x", nil, PublishOptions{}); err == nil {
+		t.Error("PublishTo an undeclared exchange succeeded")
+	}
+}
+
+func TestTopicMatch(t *testing.T) {
+	tests := []struct {
+		pattern, key string
+		want         bool
+	}{
+		{"order.*", "order.created", true},
+		{"order.*", "order.created.eu", false},
+		{"order.*", "order", false},
+		{"order.#", "order", true},
+		{"order.#", "order.created.eu", true},
+		{"#.eu", "order.created.eu", true},
+		{"*.created.*", "order.created.eu", true},
+		{"#", "", true},
+		{"order.created", "order.updated", false},
+	}
+	for _, tt := range tests {
+		if got := topicMatch(strings.Split(tt.pattern, "."), strings.Split(tt.key, ".")); got != tt.want {
+			t.Errorf("topicMatch(%q, %q) = %v, want %v", tt.pattern, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestMemoryNackRequeue(t *testing.T) {
+	useMemory(t)
+
+	const queue = "test.requeue"
+	if err := Publish(context.Background(), queue, []byte("again"), PublishOptions{}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	sub, err := transport.Consume(queue, "requeue", ConsumerOptions{Prefetch: 1})
+	if err != nil {
+		t.Fatalf("Consume: %v", err)
+	}
+	defer sub.Cancel()
+
+	next := func() amqp.Delivery {
+		t.Helper()
+		select {
+		case d := <-sub.Deliveries():
+			return d
+		case <-time.After(5 * time.Second):
+			t.Fatal("message not delivered")
+			return amqp.Delivery{}
+		}
+	}
+
+	first := next()
+	if first.Redelivered {
+		t.Fatal("first delivery marked as redelivered")
+	}
+	if err := first.Nack(false, true); err != nil {
+		t.Fatalf("Nack: %v", err)
+	}
+
+	second := next()
+	if !second.Redelivered || string(second.Body) != "again" {
+		t.Fatalf("requeued delivery: redelivered=%v body=%q", second.Redelivered, second.Body)
+	}
+	if err := second.Ack(false); err != nil {
+		t.Fatalf("Ack: %v", err)
+	}
+	if err := second.Ack(false); err == nil {
+		t.Error("second Ack of the same delivery tag succeeded")
+	}
+	if n := queueLen(t, queue); n != 0 {
+		t.Fatalf("queue has %d messages after ack", n)
+	}
+}
+
+func TestMemoryPrefetchMultipleAck(t *testing.T) {
+	useMemory(t)
+
+	const queue = "test.prefetch"
+	ctx := context.Background()
+	for _, body := range []string{"1", "2", "3"} {
+		if err := Publish(ctx, queue, []byte(body), PublishOptions{}); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+
+	sub, err := transport.Consume(queue, "prefetch", ConsumerOptions{Prefetch: 2})
+	if err != nil {
+		t.Fatalf("Consume: %v", err)
+	}
+	defer sub.Cancel()
+
+	// 预取上限为 2, 第三条消息在确认之前保留在队列中
+	var last amqp.Delivery
+	for i := 0; i < 2; i++ {
+		last = <-sub.Deliveries()
+	}
+	if n := queueLen(t, queue); n != 1 {
+		t.Fatalf("queue has %d ready messages with prefetch 2, want 1", n)
+	}
+
+	// multiple 确认之前的所有消息, 释放预取额度
+	if err := last.Ack(true); err != nil {
+		t.Fatalf("Ack multiple: %v", err)
+	}
+	select {
+	case d := <-sub.Deliveries():
+		if string(d.Body) != "3" {
+			t.Fatalf("third delivery body = %q", d.Body)
+		}
+		d.Ack(false)
+	case <-time.After(5 * time.Second):
+		t.Fatal("third message not delivered after multiple ack")
+	}
+}
+
+func TestMemoryQueueMismatch(t *testing.T) {
+	useMemory(t)
+
+	err := transport.Declare(func(d Declarer) error {
+		if _, err := d.QueueDeclare("test.mismatch", true, false, false, false, nil); err != nil {
+			return err
+		}
+		_, err := d.QueueDeclare("test.mismatch", false, false, false, false, nil)
+		return err
+	})
+	var amqpErr *amqp.Error
+	if !errors.As(err, &amqpErr) || amqpErr.Code != amqp.PreconditionFailed {
+		t.Fatalf("redeclare error = %v, want PRECONDITION_FAILED", err)
+	}
+}
+
+func TestPublishWithoutTransport(t *testing.T) {
+	consumerMu.Lock()
+	consumers = nil
+	consumerMu.Unlock()
+	setTransport(nil)
+
+	err := Publish(context.Background(), "test.none", []byte("x"), PublishOptions{})
+	if !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("Publish error = %v, want ErrNotConnected", err)
+	}
+	if err := NewRabbitmq(Config{}); err != nil {
+		t.Fatalf("NewRabbitmq without host: %v", err)
+	}
+	if h := Health(); h.Enabled {
+		t.Fatalf("Health().Enabled = true without host, want the in-process bus to require MQ_TRANSPORT=memory")
+	}
+}
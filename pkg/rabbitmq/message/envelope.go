@@ -0,0 +1,67 @@
+// Package message 基于 RabbitMQ 的类型化 JSON 消息
+// 消息统一封装为 Envelope, 同一队列上按消息类型 (type) 分发到不同的处理函数
+package message
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ContentType 消息的内容类型
+const ContentType = "application/json"
+
+// ErrInvalidEnvelope 消息不是合法的 Envelope
+var ErrInvalidEnvelope = errors.New("消息格式错误")
+
+// Envelope 标准消息信封
+type Envelope struct {
+	ID         string          `json:"id"`                 // 消息 ID, 同时作为 AMQP message-id, 可用于去重
+	Type       string          `json:"type"`               // 消息类型, 如 user.created
+	Version    int             `json:"version"`            // 消息结构版本, 从 1 开始
+	OccurredAt time.Time       `json:"occurred_at"`        // 事件发生时间
+	TraceID    string          `json:"trace_id,omitempty"` // 链路追踪 ID
+	Payload    json.RawMessage `json:"payload"`            // 业务数据
+}
+
+// validate 校验信封必填字段
+func (e Envelope) validate() error {
+	switch {
+	case e.ID == "":
+		return fmt.Errorf("%w: 缺少 id", ErrInvalidEnvelope)
+	case e.Type == "":
+		return fmt.Errorf("%w: 缺少 type", ErrInvalidEnvelope)
+	case e.Version < 1:
+		return fmt.Errorf("%w: version 无效: %d", ErrInvalidEnvelope, e.Version)
+	case e.OccurredAt.IsZero():
+		return fmt.Errorf("%w: 缺少 occurred_at", ErrInvalidEnvelope)
+	case len(e.Payload) == 0:
+		return fmt.Errorf("%w: 缺少 payload", ErrInvalidEnvelope)
+	}
+	return nil
+}
+
+// decodeEnvelope 解析并校验消息体
+func decodeEnvelope(body []byte) (Envelope, error) {
+	var env Envelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return env, fmt.Errorf("%w: %v", ErrInvalidEnvelope, err)
+	}
+	return env, env.validate()
+}
+
+type traceIDKey struct{}
+
+// WithTraceID 在 ctx 中设置链路追踪 ID, Publish 未指定 TraceID 时使用
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// TraceID 读取 ctx 中的链路追踪 ID
+// 处理函数的 ctx 已携带收到消息的 TraceID, 在处理函数中发布的消息会自动沿用
+func TraceID(ctx context.Context) string {
+	id, _ := ctx.Value(traceIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,83 @@
+package message
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"app/pkg/rabbitmq"
+)
+
+// Options 消息发布选项
+type Options struct {
+	Version    int       // 消息结构版本, 默认 1
+	TraceID    string    // 链路追踪 ID, 为空时使用 ctx 中的 TraceID
+	OccurredAt time.Time // 事件发生时间, 默认当前时间
+
+	rabbitmq.PublishOptions // AMQP 消息属性 (ContentType 和 MessageID 由信封决定)
+}
+
+// Publish 将 payload 封装为 Envelope 发布到指定队列, 并等待 Broker 确认
+func Publish[T any](ctx context.Context, queue, msgType string, payload T, opts ...Options) error {
+	body, o, err := encode(ctx, msgType, payload, opts)
+	if err != nil {
+		return err
+	}
+	return rabbitmq.Publish(ctx, queue, body, o)
+}
+
+// PublishTo 将 payload 封装为 Envelope 发布到指定交换机, 并等待 Broker 确认
+func PublishTo[T any](ctx context.Context, exchange, routingKey, msgType string, payload T, opts ...Options) error {
+	body, o, err := encode(ctx, msgType, payload, opts)
+	if err != nil {
+		return err
+	}
+	return rabbitmq.PublishTo(ctx, exchange, routingKey, body, o)
+}
+
+// encode 构造信封并序列化, 返回消息体和 AMQP 发布选项
+func encode[T any](ctx context.Context, msgType string, payload T, opts []Options) ([]byte, rabbitmq.PublishOptions, error) {
+	var o Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, o.PublishOptions, fmt.Errorf("marshal %s payload: %w", msgType, err)
+	}
+
+	env := Envelope{
+		ID:         uuid.NewString(),
+		Type:       msgType,
+		Version:    o.Version,
+		OccurredAt: o.OccurredAt,
+		TraceID:    o.TraceID,
+		Payload:    raw,
+	}
+	if env.Version == 0 {
+		env.Version = 1
+	}
+	if env.OccurredAt.IsZero() {
+		env.OccurredAt = time.Now()
+	}
+	if env.TraceID == "" {
+		env.TraceID = TraceID(ctx)
+	}
+	if err := env.validate(); err != nil {
+		return nil, o.PublishOptions, err
+	}
+
+	body, err := json.Marshal(env)
+	if err != nil {
+		return nil, o.PublishOptions, fmt.Errorf("marshal %s envelope: %w", msgType, err)
+	}
+
+	po := o.PublishOptions
+	po.ContentType = ContentType
+	po.MessageID = env.ID
+	return body, po, nil
+}
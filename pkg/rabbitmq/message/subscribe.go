@@ -0,0 +1,90 @@
+package message
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"app/pkg/rabbitmq"
+)
+
+// Handler 类型化消息处理函数, env 为消息信封 (Payload 为原始 JSON), payload 为解析后的业务数据
+type Handler[T any] func(ctx context.Context, env Envelope, payload T) error
+
+// router 单个队列的消息分发器
+type router struct {
+	queue    string
+	mu       sync.RWMutex
+	handlers map[string]func(ctx context.Context, env Envelope) error
+}
+
+var (
+	routersMu sync.Mutex
+	routers   = make(map[string]*router)
+)
+
+// Subscribe 订阅队列上指定类型的消息
+// 同一队列可以多次调用, 注册不同类型的处理函数, 共用一个消费者 (通过 rabbitmq.Register 注册,
+// 由 rabbitmq.StartConsumers 启动); opts 只在队列第一次订阅时生效
+//
+// 消息格式错误或 payload 无法解析时不重试, 直接进入死信队列;
+// 没有对应处理函数的消息类型按处理失败重试 (滚动发布时可能由新版本实例处理)
+func Subscribe[T any](queue, msgType string, handler Handler[T], opts ...rabbitmq.ConsumerOptions) {
+	routersMu.Lock()
+	r, ok := routers[queue]
+	if !ok {
+		r = &router{queue: queue, handlers: make(map[string]func(context.Context, Envelope) error)}
+		routers[queue] = r
+	}
+	routersMu.Unlock()
+
+	r.mu.Lock()
+	if _, exists := r.handlers[msgType]; exists {
+		r.mu.Unlock()
+		panic(fmt.Sprintf("message: 队列 %s 的消息类型 %s 已订阅", queue, msgType))
+	}
+	r.handlers[msgType] = func(ctx context.Context, env Envelope) error {
+		var payload T
+		if err := json.Unmarshal(env.Payload, &payload); err != nil {
+			return rabbitmq.Permanent(fmt.Errorf("%w: 解析 %s payload 失败: %v", ErrInvalidEnvelope, env.Type, err))
+		}
+		return handler(ctx, env, payload)
+	}
+	r.mu.Unlock()
+
+	if !ok {
+		var o rabbitmq.ConsumerOptions
+		if len(opts) > 0 {
+			o = opts[0]
+		}
+		rabbitmq.Register(rabbitmq.Consumer{
+			Queue:       queue,
+			Handler:     r.dispatch,
+			Options:     o,
+			Description: "typed messages",
+		})
+	}
+}
+
+// dispatch 解析信封并按消息类型分发
+func (r *router) dispatch(ctx context.Context, d amqp.Delivery) error {
+	env, err := decodeEnvelope(d.Body)
+	if err != nil {
+		return rabbitmq.Permanent(err)
+	}
+
+	r.mu.RLock()
+	handle, ok := r.handlers[env.Type]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("队列 %s 没有消息类型 %s 的处理函数", r.queue, env.Type)
+	}
+
+	if env.TraceID != "" {
+		ctx = WithTraceID(ctx, env.TraceID)
+	}
+	return handle(ctx, env)
+}
@@ -0,0 +1,27 @@
+package rabbitmq
+
+import "context"
+
+// Middleware 消费者中间件, 包装处理函数 (如去重、日志、指标)
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// chain 按顺序组合中间件, 第一个中间件在最外层
+func chain(handler HandlerFunc, middlewares []Middleware) HandlerFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+type queueKey struct{}
+
+// withQueue 在 ctx 中记录消息所在的队列
+func withQueue(ctx context.Context, queue string) context.Context {
+	return context.WithValue(ctx, queueKey{}, queue)
+}
+
+// QueueFromContext 返回处理中消息所在的队列名称
+func QueueFromContext(ctx context.Context) string {
+	queue, _ := ctx.Value(queueKey{}).(string)
+	return queue
+}
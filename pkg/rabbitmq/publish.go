@@ -0,0 +1,83 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+var (
+	// ErrNotConnected RabbitMQ 未初始化或连接已断开
+	ErrNotConnected = errors.New("RabbitMQ 未连接")
+	// ErrNacked Broker 拒绝了消息 (publisher confirm 返回 nack)
+	ErrNacked = errors.New("消息未被 Broker 确认")
+)
+
+// defaultPublishTimeout ctx 未设置超时时, 等待 Broker 确认的最长时间
+const defaultPublishTimeout = 5 * time.Second
+
+// PublishOptions 消息发布选项
+type PublishOptions struct {
+	ContentType   string        // 内容类型, 默认 text/plain
+	Headers       amqp.Table    // 自定义消息头
+	MessageID     string        // 消息 ID, 为空时自动生成 UUID
+	CorrelationID string        // 关联 ID
+	TTL           time.Duration // 消息过期时间, 0 表示不过期
+	Priority      uint8         // 优先级 (0-9, 需要队列开启 x-max-priority)
+	Transient     bool          // 非持久化消息 (默认持久化)
+}
+
+// Publish 发布消息到指定队列, 并等待 Broker 确认 (publisher confirms)
+// 队列按 RegisterQueue 注册的声明创建, 未注册时使用 DefaultQueueSpec
+// 返回 nil 表示消息已被 Broker 接收并持久化
+func Publish(ctx context.Context, queueName string, body []byte, opts PublishOptions) error {
+	if err := ensureQueue(queueName); err != nil {
+		return err
+	}
+	return publish(ctx, "", queueName, opts.publishing(body))
+}
+
+// publish 通过当前传输层发布消息并等待确认
+func publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) error {
+	if transport == nil {
+		return ErrNotConnected
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultPublishTimeout)
+		defer cancel()
+	}
+	return transport.Publish(ctx, exchange, routingKey, msg)
+}
+
+// publishing 根据发布选项构造消息
+func (opts PublishOptions) publishing(body []byte) amqp.Publishing {
+	msg := amqp.Publishing{
+		ContentType:   opts.ContentType,
+		Headers:       opts.Headers,
+		MessageId:     opts.MessageID,
+		CorrelationId: opts.CorrelationID,
+		Priority:      opts.Priority,
+		Timestamp:     time.Now(),
+		DeliveryMode:  amqp.Persistent, // 消息持久化
+		Body:          body,
+	}
+	if msg.ContentType == "" {
+		msg.ContentType = "text/plain"
+	}
+	if msg.MessageId == "" {
+		msg.MessageId = uuid.NewString()
+	}
+	if opts.TTL > 0 {
+		msg.Expiration = strconv.FormatInt(opts.TTL.Milliseconds(), 10)
+	}
+	if opts.Transient {
+		msg.DeliveryMode = amqp.Transient
+	}
+	return msg
+}
@@ -0,0 +1,226 @@
+package rabbitmq
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// 队列类型
+const (
+	QueueClassic = "classic" // 经典队列 (默认)
+	QueueQuorum  = "quorum"  // 仲裁队列, 多节点复制, 必须持久化
+)
+
+// 队列满时的溢出策略 (x-overflow)
+const (
+	OverflowDropHead         = "drop-head"          // 丢弃最旧的消息 (默认)
+	OverflowRejectPublish    = "reject-publish"     // 拒绝新消息, 发布方收到 nack
+	OverflowRejectPublishDLX = "reject-publish-dlx" // 拒绝新消息并投递到死信交换机
+)
+
+// ErrQueueMismatch 队列已存在且参数与声明不一致
+var ErrQueueMismatch = errors.New("队列参数不一致")
+
+// QueueSpec 队列声明, 生产者和消费者使用同一份声明, 避免参数不一致
+type QueueSpec struct {
+	Name       string
+	Durable    bool          // 持久化, Broker 重启后保留
+	AutoDelete bool          // 最后一个消费者断开后自动删除 (未消费的消息会丢失)
+	Exclusive  bool          // 独占队列, 仅当前连接可用, 连接断开后删除
+	Type       string        // 队列类型: QueueClassic / QueueQuorum, 默认 classic
+	MaxLength  int           // 最大消息数 (x-max-length), 0 表示不限制
+	Overflow   string        // 溢出策略 (x-overflow), 需配合 MaxLength 使用
+	MessageTTL time.Duration // 消息过期时间 (x-message-ttl), 0 表示不过期
+	// 死信交换机 (x-dead-letter-exchange), 消息被拒绝、过期或溢出时投递到该交换机
+	DeadLetterExchange   string
+	DeadLetterRoutingKey string     // 死信路由键 (x-dead-letter-routing-key), 为空时使用原路由键
+	MaxPriority          int        // 最大优先级 (x-max-priority), 0 表示不支持优先级
+	Args                 amqp.Table // 其他参数
+}
+
+// DefaultQueueSpec 未注册的队列使用的默认声明: 持久化, 不自动删除
+func DefaultQueueSpec(name string) QueueSpec {
+	return QueueSpec{Name: name, Durable: true}
+}
+
+// validate 校验声明是否合法
+func (s QueueSpec) validate() error {
+	if s.Name == "" {
+		return errors.New("队列名称不能为空")
+	}
+	switch s.Type {
+	case "", QueueClassic:
+	case QueueQuorum:
+		if !s.Durable || s.AutoDelete || s.Exclusive {
+			return fmt.Errorf("仲裁队列 %s 必须持久化, 且不能自动删除或独占", s.Name)
+		}
+		if s.MaxPriority > 0 {
+			return fmt.Errorf("仲裁队列 %s 不支持优先级", s.Name)
+		}
+	default:
+		return fmt.Errorf("不支持的队列类型: %s", s.Type)
+	}
+	if s.Overflow != "" && s.MaxLength <= 0 {
+		return fmt.Errorf("队列 %s 设置了 Overflow, 但未设置 MaxLength", s.Name)
+	}
+	return nil
+}
+
+// arguments 转换为队列声明参数
+func (s QueueSpec) arguments() amqp.Table {
+	args := amqp.Table{}
+	for k, v := range s.Args {
+		args[k] = v
+	}
+	if s.Type != "" {
+		args["x-queue-type"] = s.Type
+	}
+	if s.MaxLength > 0 {
+		args["x-max-length"] = int64(s.MaxLength)
+	}
+	if s.Overflow != "" {
+		args["x-overflow"] = s.Overflow
+	}
+	if s.MessageTTL > 0 {
+		args["x-message-ttl"] = s.MessageTTL.Milliseconds()
+	}
+	if s.DeadLetterExchange != "" {
+		args["x-dead-letter-exchange"] = s.DeadLetterExchange
+	}
+	if s.DeadLetterRoutingKey != "" {
+		args["x-dead-letter-routing-key"] = s.DeadLetterRoutingKey
+	}
+	if s.MaxPriority > 0 {
+		args["x-max-priority"] = int64(s.MaxPriority)
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	return args
+}
+
+// String 声明摘要, 用于错误信息
+func (s QueueSpec) String() string {
+	parts := []string{
+		fmt.Sprintf("durable=%v", s.Durable),
+		fmt.Sprintf("auto_delete=%v", s.AutoDelete),
+		fmt.Sprintf("exclusive=%v", s.Exclusive),
+	}
+	for k, v := range s.arguments() {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, v))
+	}
+	return strings.Join(parts, " ")
+}
+
+var (
+	queueMu        sync.Mutex
+	queueSpecs     = make(map[string]QueueSpec)
+	declaredQueues = make(map[string]bool) // 当前连接 (或传输层) 上已声明过的队列
+)
+
+// RegisterQueue 注册队列声明 (一般在 init 中调用)
+// 声明无效或同名队列已注册为不同参数时 panic, 便于在启动阶段发现配置错误
+func RegisterQueue(spec QueueSpec) {
+	if err := spec.validate(); err != nil {
+		panic(fmt.Sprintf("rabbitmq: 注册队列失败: %v", err))
+	}
+
+	queueMu.Lock()
+	defer queueMu.Unlock()
+
+	if existing, ok := queueSpecs[spec.Name]; ok && !reflect.DeepEqual(existing, spec) {
+		panic(fmt.Sprintf("rabbitmq: 注册队列失败: 队列 %s 已注册为 (%s), 与 (%s) 不一致", spec.Name, existing, spec))
+	}
+	queueSpecs[spec.Name] = spec
+}
+
+// LookupQueue 返回队列声明, 未注册时返回 DefaultQueueSpec
+func LookupQueue(name string) QueueSpec {
+	queueMu.Lock()
+	defer queueMu.Unlock()
+
+	if spec, ok := queueSpecs[name]; ok {
+		return spec
+	}
+	return DefaultQueueSpec(name)
+}
+
+// ensureQueue 按注册的声明声明队列, 同一连接上只声明一次
+// 自动删除的队列随时可能被删除, 每次都重新声明
+func ensureQueue(name string) error {
+	if transport == nil {
+		return ErrNotConnected
+	}
+
+	queueMu.Lock()
+	declared := declaredQueues[name]
+	queueMu.Unlock()
+	if declared {
+		return nil
+	}
+
+	spec := LookupQueue(name)
+	err := transport.Declare(func(d Declarer) error {
+		return declareQueueSpec(d, spec)
+	})
+	if err != nil {
+		return err
+	}
+
+	if !spec.AutoDelete {
+		queueMu.Lock()
+		declaredQueues[name] = true
+		queueMu.Unlock()
+	}
+	return nil
+}
+
+// resetDeclarations 清空已声明队列的缓存 (重连或切换传输层后队列需要重新声明)
+func resetDeclarations() {
+	queueMu.Lock()
+	clear(declaredQueues)
+	queueMu.Unlock()
+
+	delayMu.Lock()
+	clear(delayDeclared)
+	delayMu.Unlock()
+}
+
+// declareQueueSpec 声明队列, 参数与已存在的队列冲突时返回 ErrQueueMismatch
+func declareQueueSpec(d Declarer, spec QueueSpec) error {
+	_, err := d.QueueDeclare(
+		spec.Name,        // name
+		spec.Durable,     // durable
+		spec.AutoDelete,  // delete when unused
+		spec.Exclusive,   // exclusive
+		false,            // no-wait
+		spec.arguments(), // arguments
+	)
+
+	var amqpErr *amqp.Error
+	if errors.As(err, &amqpErr) && amqpErr.Code == amqp.PreconditionFailed {
+		return fmt.Errorf("%w: 队列 %s 已存在, 与声明 (%s) 不一致: %s; 请调整 QueueSpec 或删除后重建队列",
+			ErrQueueMismatch, spec.Name, spec, amqpErr.Reason)
+	}
+	if err != nil {
+		return fmt.Errorf("declare queue %s: %w", spec.Name, err)
+	}
+	return nil
+}
+
+// withChannel 在临时 Channel 上执行声明操作
+// 声明失败会导致 Channel 被 Broker 关闭, 使用临时 Channel 避免影响正在使用的 Channel
+func withChannel(conn *amqp.Connection, fn func(d Declarer) error) error {
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("open channel: %w", err)
+	}
+	defer ch.Close()
+	return fn(ch)
+}
@@ -0,0 +1,75 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Consumer 队列消费者定义
+type Consumer struct {
+	Queue       string                  // 队列名称 (唯一)
+	Handler     HandlerFunc             // 处理函数, 与 Run 二选一
+	Run         func(body []byte) error // 只读取消息体的处理函数
+	Options     ConsumerOptions         // 并发、预取、重试等选项
+	Description string                  // 描述
+}
+
+var (
+	registryMu          sync.Mutex
+	registeredConsumers []Consumer
+	consumersStarted    bool
+)
+
+// Register 注册队列消费者 (一般在 init 中调用), 由 StartConsumers 统一启动
+// 队列名称为空、未设置处理函数或重复注册时 panic, 便于在启动阶段发现配置错误
+func Register(c Consumer) {
+	if c.Queue == "" {
+		panic("rabbitmq: 注册消费者失败: 队列名称为空")
+	}
+	if (c.Handler == nil) == (c.Run == nil) {
+		panic(fmt.Sprintf("rabbitmq: 注册消费者失败 [%s]: Handler 和 Run 需要且只能设置一个", c.Queue))
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for _, existing := range registeredConsumers {
+		if existing.Queue == c.Queue {
+			panic(fmt.Sprintf("rabbitmq: 注册消费者失败: 队列重复 [%s]", c.Queue))
+		}
+	}
+	registeredConsumers = append(registeredConsumers, c)
+}
+
+// RegisteredConsumers 返回所有已注册的消费者
+func RegisteredConsumers() []Consumer {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return append([]Consumer(nil), registeredConsumers...)
+}
+
+// StartConsumers 启动所有已注册的消费者 (重复调用无效), 返回启动的消费者数量
+func StartConsumers() int {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if consumersStarted {
+		return 0
+	}
+	consumersStarted = true
+
+	for _, c := range registeredConsumers {
+		handler := c.Handler
+		if handler == nil {
+			run := c.Run
+			handler = func(ctx context.Context, d amqp.Delivery) error {
+				return run(d.Body)
+			}
+		}
+		Consume(c.Queue, handler, c.Options)
+	}
+	return len(registeredConsumers)
+}
@@ -0,0 +1,259 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// directReplyTo RabbitMQ 内置的伪队列, 回复直接投递给发起请求的 Channel, 无需声明回复队列
+const directReplyTo = "amq.rabbitmq.reply-to"
+
+// rpcErrorHeader 服务端处理失败时, 回复消息中记录错误信息的消息头
+const rpcErrorHeader = "x-rpc-error"
+
+// defaultCallTimeout ctx 未设置超时时, Call 等待回复的最长时间
+const defaultCallTimeout = 30 * time.Second
+
+// ErrNoRoute 请求无法路由到队列 (队列不存在)
+var ErrNoRoute = errors.New("请求无法路由到队列")
+
+// RemoteError 服务端处理函数返回的错误
+type RemoteError struct {
+	Message string
+}
+
+func (e *RemoteError) Error() string {
+	return "rpc: " + e.Message
+}
+
+// RPCHandler RPC 处理函数, 返回的数据作为回复; 返回错误时调用方收到 *RemoteError
+type RPCHandler func(ctx context.Context, req []byte) ([]byte, error)
+
+// rpcReply 回复或投递失败的结果
+type rpcReply struct {
+	body []byte
+	err  error
+}
+
+// rpcClient RPC 调用方: 所有请求共用一个 Channel, 按 CorrelationId 匹配回复
+type rpcClient struct {
+	mu      sync.Mutex
+	conn    *amqp.Connection
+	ch      *amqp.Channel
+	pending map[string]chan rpcReply
+}
+
+var rpc = &rpcClient{pending: make(map[string]chan rpcReply)}
+
+// Call 发送请求到指定队列并等待回复 (direct reply-to)
+// ctx 未设置超时时使用 30s; 超时的请求在 Broker 中同时过期, 不会被服务端处理
+func Call(ctx context.Context, queue string, body []byte, opts ...PublishOptions) ([]byte, error) {
+	var o PublishOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultCallTimeout)
+		defer cancel()
+	}
+
+	if err := ensureQueue(queue); err != nil {
+		return nil, err
+	}
+
+	msg := o.publishing(body)
+	msg.CorrelationId = uuid.NewString()
+	msg.ReplyTo = directReplyTo
+	msg.DeliveryMode = amqp.Transient
+	if deadline, ok := ctx.Deadline(); ok {
+		msg.Expiration = strconv.FormatInt(max(time.Until(deadline).Milliseconds(), 1), 10)
+	}
+
+	reply := make(chan rpcReply, 1)
+	ch, err := rpc.register(msg.CorrelationId, reply)
+	if err != nil {
+		return nil, err
+	}
+	defer rpc.unregister(msg.CorrelationId)
+
+	if ch == nil {
+		// 进程内消息总线: 回复由 memoryTransport.Publish 直接交给 rpc
+		err = transport.Publish(ctx, "", queue, msg)
+	} else {
+		// mandatory: 队列不存在时 Broker 退回消息, 立即返回 ErrNoRoute 而不是等待超时
+		err = ch.PublishWithContext(ctx, "", queue, true, false, msg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("publish request to %s: %w", queue, err)
+	}
+
+	select {
+	case r := <-reply:
+		return r.body, r.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("wait reply from %s: %w", queue, ctx.Err())
+	}
+}
+
+// register 登记等待中的请求, 返回用于发布请求的 Channel (进程内消息总线返回 nil)
+func (c *rpcClient) register(correlationID string, reply chan rpcReply) (*amqp.Channel, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := transport.(*amqpTransport); !ok {
+		c.pending[correlationID] = reply
+		return nil, nil
+	}
+	if err := c.ensureChannel(); err != nil {
+		return nil, err
+	}
+	c.pending[correlationID] = reply
+	return c.ch, nil
+}
+
+func (c *rpcClient) unregister(correlationID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.pending, correlationID)
+}
+
+// ensureChannel 创建 (或在重连后重建) RPC Channel 并订阅 direct reply-to (调用方需持有 mu)
+func (c *rpcClient) ensureChannel() error {
+	conn := connection()
+	if conn == nil {
+		return ErrNotConnected
+	}
+	if c.ch != nil && c.conn == conn && !c.ch.IsClosed() {
+		return nil
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("open rpc channel: %w", err)
+	}
+
+	// direct reply-to 必须使用 auto-ack 模式订阅
+	replies, err := ch.Consume(directReplyTo, "", true, false, false, false, nil)
+	if err != nil {
+		ch.Close()
+		return fmt.Errorf("consume %s: %w", directReplyTo, err)
+	}
+	returns := ch.NotifyReturn(make(chan amqp.Return, 16))
+
+	c.conn, c.ch = conn, ch
+	go c.dispatch(ch, replies, returns)
+	return nil
+}
+
+// dispatch 按 CorrelationId 将回复和退回的请求分发给等待中的调用
+func (c *rpcClient) dispatch(ch *amqp.Channel, replies <-chan amqp.Delivery, returns <-chan amqp.Return) {
+	for replies != nil || returns != nil {
+		select {
+		case d, ok := <-replies:
+			if !ok {
+				replies = nil
+				continue
+			}
+			c.deliverReply(d)
+		case ret, ok := <-returns:
+			if !ok {
+				returns = nil
+				continue
+			}
+			c.deliver(ret.CorrelationId, rpcReply{err: fmt.Errorf("%w: %s (%s)", ErrNoRoute, ret.RoutingKey, ret.ReplyText)})
+		}
+	}
+
+	// Channel 已关闭, 尚未收到回复的请求无法再收到回复
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ch != ch {
+		return
+	}
+	for id, reply := range c.pending {
+		select {
+		case reply <- rpcReply{err: fmt.Errorf("rpc channel closed: %w", ErrNotConnected)}:
+		default:
+		}
+		delete(c.pending, id)
+	}
+}
+
+// deliverReply 将回复 (或服务端返回的错误) 交给等待中的调用
+func (c *rpcClient) deliverReply(d amqp.Delivery) {
+	r := rpcReply{body: d.Body}
+	if msg, ok := d.Headers[rpcErrorHeader].(string); ok {
+		r = rpcReply{err: &RemoteError{Message: msg}}
+	}
+	c.deliver(d.CorrelationId, r)
+}
+
+// deliver 将结果交给等待中的调用, 调用已超时返回时丢弃
+func (c *rpcClient) deliver(correlationID string, r rpcReply) {
+	c.mu.Lock()
+	reply, ok := c.pending[correlationID]
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+	select {
+	case reply <- r:
+	default:
+	}
+}
+
+// Serve 注册 RPC 服务端 (与 Register 相同, 由 StartConsumers 启动)
+// 处理函数的返回值回复给调用方; 处理失败时回复错误信息, 请求不会重试
+func Serve(queue string, handler RPCHandler, opts ...ConsumerOptions) {
+	var o ConsumerOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	Register(Consumer{
+		Queue:       queue,
+		Handler:     rpcHandler(handler),
+		Options:     o,
+		Description: "rpc",
+	})
+}
+
+// rpcHandler 将 RPCHandler 包装为消息处理函数
+func rpcHandler(handler RPCHandler) HandlerFunc {
+	return func(ctx context.Context, d amqp.Delivery) error {
+		if d.ReplyTo == "" {
+			log.Printf("Drop rpc request %s from queue %s: missing reply-to", d.CorrelationId, QueueFromContext(ctx))
+			return nil
+		}
+
+		reply := amqp.Publishing{
+			CorrelationId: d.CorrelationId,
+			DeliveryMode:  amqp.Transient,
+			Timestamp:     time.Now(),
+		}
+		resp, err := handler(ctx, d.Body)
+		if err != nil {
+			reply.Headers = amqp.Table{rpcErrorHeader: err.Error()}
+		} else {
+			reply.Body = resp
+		}
+
+		// 回复失败时调用方会超时, 重新处理请求没有意义, 只记录日志
+		pubCtx, cancel := context.WithTimeout(context.Background(), defaultPublishTimeout)
+		defer cancel()
+		if err := publish(pubCtx, "", d.ReplyTo, reply); err != nil {
+			log.Printf("Failed to reply rpc request %s: %s", d.CorrelationId, err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,60 @@
+package rabbitmq
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// ConsumerStats 队列消费统计 (进程启动以来)
+type ConsumerStats struct {
+	Queue        string `json:"queue"`
+	Processed    uint64 `json:"processed"`     // 处理成功
+	Failed       uint64 `json:"failed"`        // 处理失败 (含重试和进入死信队列)
+	DeadLettered uint64 `json:"dead_lettered"` // 进入死信队列
+	InFlight     int64  `json:"in_flight"`     // 正在处理
+}
+
+type queueStats struct {
+	processed    atomic.Uint64
+	failed       atomic.Uint64
+	deadLettered atomic.Uint64
+	inFlight     atomic.Int64
+}
+
+var (
+	statsMu sync.Mutex
+	stats   = make(map[string]*queueStats)
+)
+
+// statsFor 返回队列的统计计数器
+func statsFor(queue string) *queueStats {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+
+	s, ok := stats[queue]
+	if !ok {
+		s = &queueStats{}
+		stats[queue] = s
+	}
+	return s
+}
+
+// Stats 返回所有队列的消费统计, 按队列名称排序
+func Stats() []ConsumerStats {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+
+	list := make([]ConsumerStats, 0, len(stats))
+	for queue, s := range stats {
+		list = append(list, ConsumerStats{
+			Queue:        queue,
+			Processed:    s.processed.Load(),
+			Failed:       s.failed.Load(),
+			DeadLettered: s.deadLettered.Load(),
+			InFlight:     s.inFlight.Load(),
+		})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Queue < list[j].Queue })
+	return list
+}
@@ -0,0 +1,114 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Exchange 交换机声明
+type Exchange struct {
+	Name       string     // 交换机名称
+	Kind       string     // 类型: amqp.ExchangeDirect / ExchangeTopic / ExchangeFanout / ExchangeHeaders
+	Durable    bool       // 持久化, Broker 重启后保留
+	AutoDelete bool       // 所有绑定解除后自动删除
+	Internal   bool       // 内部交换机, 只能由其他交换机路由消息
+	Args       amqp.Table // 其他参数 (如 alternate-exchange)
+}
+
+// Binding 队列绑定
+type Binding struct {
+	Queue      string     // 队列名称 (不存在时自动声明)
+	Exchange   string     // 交换机名称
+	RoutingKey string     // 路由键, topic 交换机支持 * 和 # 通配符, fanout 交换机忽略
+	Args       amqp.Table // 绑定参数, headers 交换机使用 x-match 及匹配的消息头
+}
+
+// topology 代码中定义的交换机和绑定, 连接 (及重连) 成功后按注册顺序声明
+var (
+	topologyMu sync.Mutex
+	exchanges  []Exchange
+	bindings   []Binding
+)
+
+// RegisterExchange 注册交换机 (一般在 init 中调用), 同名交换机只保留最后一次注册
+func RegisterExchange(ex Exchange) {
+	topologyMu.Lock()
+	defer topologyMu.Unlock()
+
+	for i, existing := range exchanges {
+		if existing.Name == ex.Name {
+			exchanges[i] = ex
+			return
+		}
+	}
+	exchanges = append(exchanges, ex)
+}
+
+// RegisterBinding 注册队列绑定 (一般在 init 中调用), 重复的绑定会被忽略
+func RegisterBinding(b Binding) {
+	topologyMu.Lock()
+	defer topologyMu.Unlock()
+
+	for _, existing := range bindings {
+		if existing.Queue == b.Queue && existing.Exchange == b.Exchange && existing.RoutingKey == b.RoutingKey {
+			return
+		}
+	}
+	bindings = append(bindings, b)
+}
+
+// ApplyTopology 声明所有已注册的交换机和绑定 (幂等, 可重复调用)
+// 使用临时 Channel 声明, 参数冲突时不影响正在使用的 Channel
+func ApplyTopology() error {
+	if transport == nil {
+		return ErrNotConnected
+	}
+	return transport.Declare(applyTopology)
+}
+
+// applyTopology 声明交换机和绑定, 绑定的队列按 LookupQueue 的声明创建
+func applyTopology(d Declarer) error {
+	topologyMu.Lock()
+	defer topologyMu.Unlock()
+
+	if len(exchanges) == 0 && len(bindings) == 0 {
+		return nil
+	}
+
+	for _, ex := range exchanges {
+		err := d.ExchangeDeclare(
+			ex.Name,       // name
+			ex.Kind,       // kind
+			ex.Durable,    // durable
+			ex.AutoDelete, // auto-deleted
+			ex.Internal,   // internal
+			false,         // no-wait
+			ex.Args,       // arguments
+		)
+		if err != nil {
+			return fmt.Errorf("declare exchange %s: %w", ex.Name, err)
+		}
+	}
+
+	for _, b := range bindings {
+		if err := declareQueueSpec(d, LookupQueue(b.Queue)); err != nil {
+			return err
+		}
+		if err := d.QueueBind(b.Queue, b.RoutingKey, b.Exchange, false, b.Args); err != nil {
+			return fmt.Errorf("bind queue %s to %s (%s): %w", b.Queue, b.Exchange, b.RoutingKey, err)
+		}
+	}
+
+	log.Printf(" [*] Topology applied: %d exchanges, %d bindings", len(exchanges), len(bindings))
+	return nil
+}
+
+// PublishTo 发布消息到指定交换机和路由键, 并等待 Broker 确认
+// 交换机需要预先通过 RegisterExchange 声明
+func PublishTo(ctx context.Context, exchange, routingKey string, body []byte, opts PublishOptions) error {
+	return publish(ctx, exchange, routingKey, opts.publishing(body))
+}
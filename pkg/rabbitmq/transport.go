@@ -0,0 +1,204 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// 传输层类型
+const (
+	TransportAMQP   = "amqp"   // RabbitMQ
+	TransportMemory = "memory" // 进程内消息总线, 用于本地开发和单元测试 (消息不持久化, 不跨进程)
+	TransportNone   = "none"   // 不启用消息队列
+)
+
+// Declarer 队列、交换机和绑定的声明方法 (*amqp.Channel 的方法子集)
+type Declarer interface {
+	QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+	ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error
+	QueueBind(name, key, exchange string, noWait bool, args amqp.Table) error
+}
+
+// Subscription 队列订阅
+type Subscription interface {
+	// Deliveries 投递的消息, 取消订阅后关闭
+	Deliveries() <-chan amqp.Delivery
+	// Cancel 取消订阅, 已投递未确认的消息仍需确认
+	Cancel() error
+	// Active 订阅是否仍然有效
+	Active() bool
+	// Closed 订阅意外中断 (如独立 Channel 被关闭) 时收到通知, 返回 nil 表示不会意外中断
+	Closed() <-chan *amqp.Error
+}
+
+// Transport 消息传输层, Publish / Consume 等 API 通过当前传输层收发消息
+type Transport interface {
+	// Status 连接状态
+	Status() Status
+	// Declare 执行队列、交换机和绑定的声明 (AMQP 在临时 Channel 上执行, 声明失败不影响其他 Channel)
+	Declare(fn func(d Declarer) error) error
+	// Publish 发布消息, 返回 nil 表示消息已被接收
+	Publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) error
+	// Consume 订阅队列
+	Consume(queue, tag string, opts ConsumerOptions) (Subscription, error)
+	// Get 拉取一条消息 (需要手动确认), 队列为空时 ok 为 false
+	Get(queue string) (d amqp.Delivery, ok bool, err error)
+	// Inspect 队列中等待投递的消息数
+	Inspect(queue string) (int, error)
+	// Close 关闭传输层
+	Close() error
+}
+
+// transport 当前传输层, 由 NewRabbitmq 设置
+var transport Transport
+
+// setTransport 切换传输层, 并清空已声明队列的缓存
+func setTransport(t Transport) {
+	transport = t
+	resetDeclarations()
+}
+
+// amqpTransport 基于 RabbitMQ 连接守护的传输层
+type amqpTransport struct {
+	sv *supervisor
+}
+
+func (t *amqpTransport) Status() Status {
+	t.sv.mu.RLock()
+	defer t.sv.mu.RUnlock()
+	return t.sv.status
+}
+
+func (t *amqpTransport) Declare(fn func(d Declarer) error) error {
+	conn := t.sv.connection()
+	if conn == nil {
+		return ErrNotConnected
+	}
+	return withChannel(conn, fn)
+}
+
+// Publish 在 Confirm 模式的 Channel 上发布消息并等待 Broker 确认
+func (t *amqpTransport) Publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) error {
+	ch := t.sv.publisher()
+	if ch == nil {
+		return ErrNotConnected
+	}
+
+	confirm, err := ch.PublishWithDeferredConfirmWithContext(ctx,
+		exchange,   // exchange
+		routingKey, // routing key
+		false,      // mandatory
+		false,      // immediate
+		msg,
+	)
+	if err != nil {
+		return fmt.Errorf("publish to %s: %w", routingKey, err)
+	}
+
+	acked, err := confirm.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("wait confirm from %s: %w", routingKey, err)
+	}
+	if !acked {
+		return fmt.Errorf("publish to %s: %w", routingKey, ErrNacked)
+	}
+	return nil
+}
+
+// Consume 在默认 Channel (或独立 Channel) 上订阅队列
+func (t *amqpTransport) Consume(queue, tag string, opts ConsumerOptions) (Subscription, error) {
+	ch, conn := t.sv.channel(), t.sv.connection()
+	if ch == nil || conn == nil {
+		return nil, ErrNotConnected
+	}
+	if opts.DedicatedChannel {
+		var err error
+		if ch, err = conn.Channel(); err != nil {
+			return nil, fmt.Errorf("open channel for %s: %w", queue, err)
+		}
+	}
+
+	sub, err := consumeChannel(ch, queue, tag, opts)
+	if err != nil && opts.DedicatedChannel {
+		ch.Close()
+	}
+	return sub, err
+}
+
+// consumeChannel 设置预取数量并开始消费
+func consumeChannel(ch *amqp.Channel, queue, tag string, opts ConsumerOptions) (*amqpSubscription, error) {
+	err := ch.Qos(
+		opts.Prefetch, // prefetch count
+		0,             // prefetch size
+		false,         // global
+	)
+	if err != nil {
+		return nil, fmt.Errorf("set QoS: %w", err)
+	}
+
+	msgs, err := ch.Consume(
+		queue,          // queue
+		tag,            // consumer
+		false,          // auto-ack
+		opts.Exclusive, // exclusive
+		false,          // no-local
+		false,          // no-wait
+		nil,            // args
+	)
+	if err != nil {
+		return nil, fmt.Errorf("consume queue %s: %w", queue, err)
+	}
+
+	sub := &amqpSubscription{ch: ch, tag: tag, msgs: msgs}
+	if opts.DedicatedChannel {
+		sub.closed = ch.NotifyClose(make(chan *amqp.Error, 1))
+	}
+	return sub, nil
+}
+
+func (t *amqpTransport) Get(queue string) (amqp.Delivery, bool, error) {
+	ch := t.sv.channel()
+	if ch == nil {
+		return amqp.Delivery{}, false, ErrNotConnected
+	}
+	return ch.Get(queue, false)
+}
+
+// Inspect 被动声明队列读取消息数 (队列不存在时 Broker 会关闭 Channel, 因此使用临时 Channel)
+func (t *amqpTransport) Inspect(queue string) (int, error) {
+	conn := t.sv.connection()
+	if conn == nil {
+		return 0, ErrNotConnected
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		return 0, fmt.Errorf("open channel: %w", err)
+	}
+	defer ch.Close()
+
+	q, err := ch.QueueDeclarePassive(queue, true, false, false, false, nil)
+	if err != nil {
+		return 0, fmt.Errorf("inspect queue %s: %w", queue, err)
+	}
+	return q.Messages, nil
+}
+
+func (t *amqpTransport) Close() error {
+	t.sv.close()
+	return nil
+}
+
+// amqpSubscription 基于 Channel 的订阅
+type amqpSubscription struct {
+	ch     *amqp.Channel
+	tag    string
+	msgs   <-chan amqp.Delivery
+	closed chan *amqp.Error // 仅独立 Channel
+}
+
+func (s *amqpSubscription) Deliveries() <-chan amqp.Delivery { return s.msgs }
+func (s *amqpSubscription) Cancel() error                    { return s.ch.Cancel(s.tag, false) }
+func (s *amqpSubscription) Active() bool                     { return !s.ch.IsClosed() }
+func (s *amqpSubscription) Closed() <-chan *amqp.Error       { return s.closed }
@@ -0,0 +1,147 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+var (
+	// ErrJobNotFound 任务不存在
+	ErrJobNotFound = errors.New("任务不存在")
+	// ErrJobNotScheduled 暂停状态未共享且任务未加载到当前进程的调度器, 暂停不会生效
+	ErrJobNotScheduled = errors.New("任务未在当前进程调度, 未配置数据库时无法暂停其他进程中的任务")
+)
+
+// JobStatus 任务运行状态
+type JobStatus struct {
+	Name        string     `json:"name"`
+	Spec        string     `json:"spec"`
+	Description string     `json:"description"`
+	Singleton   bool       `json:"singleton"`
+	Timeout     string     `json:"timeout"`
+	Paused      bool       `json:"paused"`
+	Scheduled   bool       `json:"scheduled"`      // 是否已加载到当前进程的调度器
+	Next        *time.Time `json:"next"`           // 下一次触发时间
+	Prev        *time.Time `json:"prev,omitempty"` // 上一次执行时间
+}
+
+var (
+	controlMu sync.RWMutex
+	entryIDs  = make(map[string]cron.EntryID) // 任务名称 -> 调度器条目
+)
+
+// Statuses 返回所有已注册任务的运行状态
+func Statuses(ctx context.Context) []JobStatus {
+	statuses := make([]JobStatus, 0, len(registeredJobs))
+	for _, job := range registeredJobs {
+		statuses = append(statuses, status(ctx, job))
+	}
+	return statuses
+}
+
+// Status 返回指定任务的运行状态
+func Status(ctx context.Context, name string) (JobStatus, error) {
+	job, ok := FindJob(name)
+	if !ok {
+		return JobStatus{}, fmt.Errorf("%w: %s", ErrJobNotFound, name)
+	}
+	return status(ctx, job), nil
+}
+
+func status(ctx context.Context, job Job) JobStatus {
+	s := JobStatus{
+		Name:        job.Name,
+		Spec:        job.Spec,
+		Description: job.Description,
+		Singleton:   job.Singleton,
+	}
+	if job.Name != "" {
+		s.Paused, _ = IsPaused(ctx, job.Name)
+	}
+	if job.Timeout > 0 {
+		s.Timeout = job.Timeout.String()
+	}
+
+	controlMu.RLock()
+	id, scheduled := entryIDs[job.Name]
+	controlMu.RUnlock()
+
+	// 优先使用当前进程调度器中的时间, 未加载时按表达式和执行历史计算
+	var next, prev time.Time
+	if scheduled && Scheduler != nil {
+		entry := Scheduler.Entry(id)
+		s.Scheduled = entry.Valid()
+		next, prev = entry.Next, entry.Prev
+	}
+	if next.IsZero() {
+		next, _ = NextRun(job, time.Now())
+	}
+	if prev.IsZero() && job.Name != "" {
+		if runs, err := store.Recent(ctx, job.Name, 1); err == nil && len(runs) > 0 {
+			prev = runs[0].StartedAt
+		}
+	}
+
+	if !next.IsZero() {
+		s.Next = &next
+	}
+	if !prev.IsZero() {
+		s.Prev = &prev
+	}
+	return s
+}
+
+// IsPaused 任务是否已暂停, 从暂停状态存储中读取
+func IsPaused(ctx context.Context, name string) (bool, error) {
+	return pauses.IsPaused(ctx, name)
+}
+
+// Pause 暂停任务, 暂停期间调度器触发时跳过执行 (不影响手动触发)
+// 配置数据库时暂停状态保存在数据库中, 对所有进程生效; 否则仅对当前进程生效, 任务未在当前进程调度时返回 ErrJobNotScheduled
+func Pause(ctx context.Context, name string) error {
+	return setPaused(ctx, name, true)
+}
+
+// Resume 恢复已暂停的任务
+func Resume(ctx context.Context, name string) error {
+	return setPaused(ctx, name, false)
+}
+
+func setPaused(ctx context.Context, name string, value bool) error {
+	job, ok := FindJob(name)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrJobNotFound, name)
+	}
+	if _, local := pauses.(*MemoryPauseStore); local && !scheduled(job.Name) {
+		return fmt.Errorf("%w: %s", ErrJobNotScheduled, name)
+	}
+	return pauses.SetPaused(ctx, job.Name, value)
+}
+
+// scheduled 任务是否已加载到当前进程的调度器
+func scheduled(name string) bool {
+	controlMu.RLock()
+	id, ok := entryIDs[name]
+	controlMu.RUnlock()
+	return ok && Scheduler != nil && Scheduler.Entry(id).Valid()
+}
+
+// Trigger 在后台立即执行一次任务, 执行结果写入执行历史
+func Trigger(name string) error {
+	job, ok := FindJob(name)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrJobNotFound, name)
+	}
+
+	go func() {
+		if err := job.execute(context.Background(), TriggerManual); err != nil {
+			fmt.Printf("⚠️  定时任务 %s 手动执行失败: %v\n", job.Name, err)
+		}
+	}()
+	return nil
+}
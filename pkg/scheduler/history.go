@@ -0,0 +1,114 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// 执行状态
+const (
+	RunStatusSuccess = "success" // 执行成功
+	RunStatusFailed  = "failed"  // 返回错误或发生 panic
+	RunStatusTimeout = "timeout" // 超过 Timeout 未完成
+)
+
+// 触发方式
+const (
+	TriggerSchedule = "schedule" // 调度器按计划触发
+	TriggerManual   = "manual"   // 手动触发
+)
+
+// JobRun 任务执行记录
+type JobRun struct {
+	ID         uint      `gorm:"primaryKey" json:"id" bson:"-"`
+	JobName    string    `gorm:"type:varchar(100);index;not null" json:"job_name" bson:"job_name"`
+	Trigger    string    `gorm:"type:varchar(20);not null" json:"trigger" bson:"trigger"`
+	Status     string    `gorm:"type:varchar(20);index;not null" json:"status" bson:"status"`
+	Error      string    `gorm:"type:text" json:"error" bson:"error"`
+	Host       string    `gorm:"type:varchar(100)" json:"host" bson:"host"`
+	StartedAt  time.Time `gorm:"index;not null" json:"started_at" bson:"started_at"`
+	FinishedAt time.Time `gorm:"not null" json:"finished_at" bson:"finished_at"`
+	DurationMs int64     `gorm:"not null" json:"duration_ms" bson:"duration_ms"`
+}
+
+// TableName 表名
+func (JobRun) TableName() string {
+	return "job_runs"
+}
+
+// RunStore 执行记录存储接口
+type RunStore interface {
+	// Save 保存一条执行记录
+	Save(ctx context.Context, run *JobRun) error
+	// Recent 按开始时间倒序返回最近的执行记录, jobName 为空时返回所有任务
+	Recent(ctx context.Context, jobName string, limit int) ([]JobRun, error)
+}
+
+// defaultRunCapacity 内存存储保留的记录条数
+const defaultRunCapacity = 500
+
+var store RunStore = NewMemoryRunStore(defaultRunCapacity)
+
+// SetRunStore 设置执行记录存储
+func SetRunStore(s RunStore) {
+	if s != nil {
+		store = s
+	}
+}
+
+// RecentRuns 查询最近的执行记录
+func RecentRuns(ctx context.Context, jobName string, limit int) ([]JobRun, error) {
+	return store.Recent(ctx, jobName, limit)
+}
+
+// MemoryRunStore 内存环形缓冲区 (未配置数据库时使用, 重启后丢失)
+type MemoryRunStore struct {
+	mu     sync.Mutex
+	runs   []JobRun
+	next   int
+	full   bool
+	lastID uint
+}
+
+// NewMemoryRunStore 创建内存存储, capacity 为最多保留的记录条数
+func NewMemoryRunStore(capacity int) *MemoryRunStore {
+	return &MemoryRunStore{
+		runs: make([]JobRun, capacity),
+	}
+}
+
+// Save 保存执行记录, 超出容量时覆盖最旧的记录
+func (s *MemoryRunStore) Save(ctx context.Context, run *JobRun) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastID++
+	run.ID = s.lastID
+	s.runs[s.next] = *run
+	s.next = (s.next + 1) % len(s.runs)
+	if s.next == 0 {
+		s.full = true
+	}
+	return nil
+}
+
+// Recent 按开始时间倒序返回最近的执行记录
+func (s *MemoryRunStore) Recent(ctx context.Context, jobName string, limit int) ([]JobRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	size := s.next
+	if s.full {
+		size = len(s.runs)
+	}
+
+	var runs []JobRun
+	for i := 1; i <= size && (limit <= 0 || len(runs) < limit); i++ {
+		run := s.runs[(s.next-i+len(s.runs))%len(s.runs)]
+		if jobName == "" || run.JobName == jobName {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
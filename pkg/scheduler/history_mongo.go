@@ -0,0 +1,49 @@
+package scheduler
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoRunStore 基于 MongoDB 的执行记录存储 (job_runs 集合)
+type MongoRunStore struct {
+	coll *mongo.Collection
+}
+
+// NewMongoRunStore 创建 MongoDB 执行记录存储
+func NewMongoRunStore(db *mongo.Database) *MongoRunStore {
+	return &MongoRunStore{coll: db.Collection(JobRun{}.TableName())}
+}
+
+// Save 保存执行记录
+func (s *MongoRunStore) Save(ctx context.Context, run *JobRun) error {
+	_, err := s.coll.InsertOne(ctx, run)
+	return err
+}
+
+// Recent 按开始时间倒序返回最近的执行记录
+func (s *MongoRunStore) Recent(ctx context.Context, jobName string, limit int) ([]JobRun, error) {
+	filter := bson.M{}
+	if jobName != "" {
+		filter["job_name"] = jobName
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "started_at", Value: -1}})
+	if limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+
+	cursor, err := s.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []JobRun
+	if err := cursor.All(ctx, &runs); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
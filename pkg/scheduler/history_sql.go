@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// GormRunStore 基于 GORM 的执行记录存储 (job_runs 表, 由 db/migrations 创建)
+type GormRunStore struct {
+	db *gorm.DB
+}
+
+// NewGormRunStore 创建 GORM 执行记录存储
+func NewGormRunStore(db *gorm.DB) *GormRunStore {
+	return &GormRunStore{db: db}
+}
+
+// Save 保存执行记录
+func (s *GormRunStore) Save(ctx context.Context, run *JobRun) error {
+	return s.db.WithContext(ctx).Create(run).Error
+}
+
+// Recent 按开始时间倒序返回最近的执行记录
+// 仅用于管理接口展示, 允许读只读副本 (可能有复制延迟)
+func (s *GormRunStore) Recent(ctx context.Context, jobName string, limit int) ([]JobRun, error) {
+	query := s.db.WithContext(ctx).Order("started_at DESC, id DESC")
+	if jobName != "" {
+		query = query.Where("job_name = ?", jobName)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var runs []JobRun
+	if err := query.Find(&runs).Error; err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestMemoryRunStoreRecent(t *testing.T) {
+	s := NewMemoryRunStore(10)
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		name := "a"
+		if i%2 == 1 {
+			name = "b"
+		}
+		run := &JobRun{JobName: name, StartedAt: start.Add(time.Duration(i) * time.Second)}
+		if err := s.Save(ctx, run); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		if run.ID != uint(i+1) {
+			t.Fatalf("Save assigned ID %d, want %d", run.ID, i+1)
+		}
+	}
+
+	runs, _ := s.Recent(ctx, "", 0)
+	if len(runs) != 4 || runs[0].ID != 4 || runs[3].ID != 1 {
+		t.Fatalf("Recent all = %v, want IDs 4..1", ids(runs))
+	}
+
+	runs, _ = s.Recent(ctx, "a", 0)
+	if got := ids(runs); fmt.Sprint(got) != "[3 1]" {
+		t.Fatalf("Recent a = %v, want [3 1]", got)
+	}
+
+	runs, _ = s.Recent(ctx, "", 3)
+	if got := ids(runs); fmt.Sprint(got) != "[4 3 2]" {
+		t.Fatalf("Recent limit 3 = %v, want [4 3 2]", got)
+	}
+}
+
+func TestMemoryRunStoreOverwritesOldest(t *testing.T) {
+	s := NewMemoryRunStore(3)
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		s.Save(ctx, &JobRun{JobName: "job"})
+	}
+
+	runs, _ := s.Recent(ctx, "job", 0)
+	if got := ids(runs); fmt.Sprint(got) != "[5 4 3]" {
+		t.Fatalf("Recent after wrap = %v, want [5 4 3]", got)
+	}
+}
+
+func ids(runs []JobRun) []uint {
+	result := make([]uint, 0, len(runs))
+	for _, r := range runs {
+		result = append(result, r.ID)
+	}
+	return result
+}
@@ -1,23 +1,222 @@
 package scheduler
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// OverlapPolicy 上一次执行尚未结束时的处理策略
+type OverlapPolicy int
+
+const (
+	OverlapAllow OverlapPolicy = iota // 允许并发执行 (默认)
+	OverlapSkip                       // 跳过本次触发
+	OverlapQueue                      // 排队, 等待上一次执行结束后再执行
 )
 
 // Job 定时任务接口
 type Job struct {
-	Spec        string // Cron 表达式
-	Handler     func() // 任务处理函数
-	Description string // 任务描述
+	Name        string                          // 任务名称 (唯一, 用于命令行手动触发)
+	Spec        string                          // Cron 表达式
+	Handler     func()                          // 任务处理函数 (无返回值, 建议使用 Run)
+	Run         func(ctx context.Context) error // 任务处理函数, 返回的错误会记录到执行历史
+	Timeout     time.Duration                   // 执行超时时间, 超时后取消 ctx, 0 表示不限制
+	Overlap     OverlapPolicy                   // 上一次执行尚未结束时的处理策略
+	Description string                          // 任务描述
+	Singleton   bool                            // 多实例部署时只允许一个实例执行 (需要设置 Name)
 }
 
 var registeredJobs []Job
 
+// hostname 当前实例标识, 写入执行记录
+var hostname, _ = os.Hostname()
+
+// cronLogger 调度器包装器日志 (记录跳过/延迟执行)
+var cronLogger = cron.VerbosePrintfLogger(log.New(os.Stdout, "scheduler: ", log.LstdFlags))
+
 // Register 注册定时任务 (在调度器启动前调用)
+// 表达式无效或任务名称重复时 panic, 便于在启动阶段发现配置错误
 func Register(job Job) {
+	// 注册时配置尚未加载, 使用兼容秒级的解析器做语法校验
+	// LoadJobs 在启动调度器前按配置重新校验, 未启用秒级表达式时 6 段表达式在此时报错
+	if _, err := secondsParser.Parse(job.Spec); err != nil {
+		panic(fmt.Sprintf("scheduler: 注册任务失败 [%s]: 无效的 Cron 表达式 %q: %v", job.Name, job.Spec, err))
+	}
+	if job.Name != "" {
+		if _, exists := FindJob(job.Name); exists {
+			panic(fmt.Sprintf("scheduler: 注册任务失败: 任务名称重复 [%s]", job.Name))
+		}
+	}
 	registeredJobs = append(registeredJobs, job)
 }
 
+// Jobs 返回所有已注册的任务
+func Jobs() []Job {
+	jobs := make([]Job, len(registeredJobs))
+	copy(jobs, registeredJobs)
+	return jobs
+}
+
+// FindJob 根据名称查找已注册的任务
+func FindJob(name string) (Job, bool) {
+	for _, job := range registeredJobs {
+		if job.Name == name {
+			return job, true
+		}
+	}
+	return Job{}, false
+}
+
+// RunJob 立即执行一次指定任务 (不经过调度器), 返回任务执行结果
+func RunJob(name string) error {
+	job, ok := FindJob(name)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrJobNotFound, name)
+	}
+
+	return job.execute(context.Background(), TriggerManual)
+}
+
+// NextRun 计算任务的下一次触发时间
+func NextRun(job Job, from time.Time) (time.Time, error) {
+	schedule, err := parser.Parse(job.Spec)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("解析 Cron 表达式失败 [%s]: %w", job.Spec, err)
+	}
+	return schedule.Next(from.In(Location())), nil
+}
+
+// execute 执行任务, Singleton 任务需要先获取分布式锁, 执行结果写入执行历史
+func (job Job) execute(ctx context.Context, trigger string) error {
+	if job.Singleton {
+		unlock, ok, err := locker.TryLock(ctx, lockKey(job), job.leaseTTL())
+		if err != nil {
+			return fmt.Errorf("获取任务锁失败: %w", err)
+		}
+		if !ok {
+			return ErrJobLocked
+		}
+		defer unlock()
+	}
+
+	run := &JobRun{
+		JobName:   job.Name,
+		Trigger:   trigger,
+		Host:      hostname,
+		StartedAt: time.Now(),
+	}
+
+	runCtx := ctx
+	if job.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, job.Timeout)
+		defer cancel()
+	}
+
+	err := job.invoke(runCtx)
+
+	run.FinishedAt = time.Now()
+	run.DurationMs = run.FinishedAt.Sub(run.StartedAt).Milliseconds()
+	switch {
+	case err == nil:
+		run.Status = RunStatusSuccess
+	case errors.Is(runCtx.Err(), context.DeadlineExceeded):
+		run.Status = RunStatusTimeout
+		run.Error = err.Error()
+	default:
+		run.Status = RunStatusFailed
+		run.Error = err.Error()
+	}
+
+	saveCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if saveErr := store.Save(saveCtx, run); saveErr != nil {
+		log.Printf("Failed to save job run %s: %s", job.Name, saveErr)
+	}
+
+	return err
+}
+
+// invoke 调用任务处理函数, 并将 panic 转换为错误
+func (job Job) invoke(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+
+	if job.Run != nil {
+		return job.Run(ctx)
+	}
+	job.Handler()
+	return nil
+}
+
+// validate 按调度器配置 (是否启用秒级表达式) 校验任务
+func (job Job) validate() error {
+	if job.Run == nil && job.Handler == nil {
+		return errors.New("未设置任务处理函数")
+	}
+	if job.Singleton && job.Name == "" {
+		return errors.New("Singleton 任务必须设置 Name")
+	}
+	return ValidateSpec(job.Spec)
+}
+
+// label 日志和错误中使用的任务标识, 未设置名称时使用描述
+func (job Job) label() string {
+	if job.Name != "" {
+		return job.Name
+	}
+	return job.Description
+}
+
+// leaseTTL 分布式锁租约时长, 不短于任务超时时间
+func (job Job) leaseTTL() time.Duration {
+	if job.Timeout > defaultLeaseTTL {
+		return job.Timeout + time.Minute
+	}
+	return defaultLeaseTTL
+}
+
+// cronJob 包装为调度器任务, 按 Overlap 策略处理重叠执行
+func (job Job) cronJob() cron.Job {
+	var wrappers []cron.JobWrapper
+	switch job.Overlap {
+	case OverlapSkip:
+		wrappers = append(wrappers, cron.SkipIfStillRunning(cronLogger))
+	case OverlapQueue:
+		wrappers = append(wrappers, cron.DelayIfStillRunning(cronLogger))
+	}
+
+	return cron.NewChain(wrappers...).Then(cron.FuncJob(func() {
+		if job.Name != "" {
+			// 读取暂停状态失败时照常执行, 避免存储故障导致任务停止
+			paused, err := IsPaused(context.Background(), job.Name)
+			if err != nil {
+				fmt.Printf("⚠️  定时任务 %s 读取暂停状态失败: %v\n", job.Name, err)
+			} else if paused {
+				fmt.Printf("⏸️  定时任务 %s 已暂停, 跳过本次触发\n", job.Name)
+				return
+			}
+		}
+
+		err := job.execute(context.Background(), TriggerSchedule)
+		if errors.Is(err, ErrJobLocked) {
+			fmt.Printf("⏭️  定时任务 %s: %v, 跳过本次触发\n", job.Name, err)
+		} else if err != nil {
+			fmt.Printf("⚠️  定时任务 %s 执行失败: %v\n", job.Name, err)
+		}
+	}))
+}
+
 // LoadJobs 加载所有已注册的任务到调度器
 func LoadJobs() error {
 	if Scheduler == nil {
@@ -29,11 +228,28 @@ func LoadJobs() error {
 		return nil
 	}
 
+	// 先按当前配置校验所有任务, 一次报告全部无效的任务, 全部通过后再加入调度器
+	var errs []error
+	for _, job := range registeredJobs {
+		if err := job.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("[%s]: %w", job.label(), err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("加载任务失败: %w", errors.Join(errs...))
+	}
+
 	for _, job := range registeredJobs {
-		if _, err := AddJob(job.Spec, job.Handler); err != nil {
-			return fmt.Errorf("加载任务失败 [%s]: %w", job.Description, err)
+		id, err := Scheduler.AddJob(job.Spec, job.cronJob())
+		if err != nil {
+			return fmt.Errorf("加载任务失败 [%s]: %w", job.label(), err)
+		}
+		if job.Name != "" {
+			controlMu.Lock()
+			entryIDs[job.Name] = id
+			controlMu.Unlock()
 		}
-		fmt.Printf("✅ 定时任务: %s (%s)\n", job.Description, job.Spec)
+		fmt.Printf("✅ 定时任务: %s (%s)\n", job.label(), job.Spec)
 	}
 
 	return nil
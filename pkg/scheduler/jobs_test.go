@@ -0,0 +1,42 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestLoadJobsRejectsSecondsSpec(t *testing.T) {
+	saved := registeredJobs
+	t.Cleanup(func() {
+		registeredJobs = saved
+		Scheduler = nil
+		controlMu.Lock()
+		clear(entryIDs)
+		controlMu.Unlock()
+	})
+	registeredJobs = nil
+
+	// 注册时兼容秒级表达式, 未开启秒级时加载失败, 一次报告所有无效任务的名称, 且不加载任何任务
+	run := func(ctx context.Context) error { return nil }
+	Register(Job{Name: "test-valid", Spec: "0 * * * *", Run: run})
+	Register(Job{Name: "test-seconds", Spec: "*/10 * * * * *", Run: run})
+	Register(Job{Name: "test-seconds-2", Spec: "0 0 * * * *", Run: run})
+	if err := Init(Options{}); err != nil {
+		t.Fatal(err)
+	}
+	err := LoadJobs()
+	if err == nil || !strings.Contains(err.Error(), "[test-seconds]") || !strings.Contains(err.Error(), "[test-seconds-2]") {
+		t.Fatalf("LoadJobs: err=%v, want error naming test-seconds and test-seconds-2", err)
+	}
+	if n := len(Scheduler.Entries()); n != 0 {
+		t.Fatalf("%d jobs scheduled after a failed load", n)
+	}
+
+	if err := Init(Options{Seconds: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadJobs(); err != nil {
+		t.Fatalf("LoadJobs with seconds: %v", err)
+	}
+}
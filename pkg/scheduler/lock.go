@@ -0,0 +1,115 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrJobLocked 任务锁已被其他实例持有
+var ErrJobLocked = errors.New("任务正在其他实例执行")
+
+// defaultLeaseTTL 锁的默认租约时长 (仅对基于租约的实现生效, 如 MongoDB/内存)
+// 持有期间每 1/3 租约时长续约一次, 任务执行时间不受租约时长限制; 进程退出后租约最迟在到期时释放
+const defaultLeaseTTL = 10 * time.Minute
+
+// Locker 分布式锁接口
+type Locker interface {
+	// TryLock 尝试获取锁, 不阻塞; 锁被占用时返回 ok=false
+	TryLock(ctx context.Context, key string, ttl time.Duration) (unlock func(), ok bool, err error)
+}
+
+var locker Locker = NewMemoryLocker()
+
+// SetLocker 设置 Singleton 任务使用的分布式锁
+func SetLocker(l Locker) {
+	if l != nil {
+		locker = l
+	}
+}
+
+// lockKey 任务锁名称
+func lockKey(job Job) string {
+	return "scheduler:" + job.Name
+}
+
+// keepAlive 在释放锁前定期续约, renew 返回 false 表示租约已丢失, 停止续约
+// 返回的 stop 函数停止续约, 可以重复调用
+func keepAlive(ttl time.Duration, renew func() bool) (stop func()) {
+	done := make(chan struct{})
+	var once sync.Once
+
+	go func() {
+		ticker := time.NewTicker(max(ttl/3, time.Millisecond))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if !renew() {
+					return
+				}
+			}
+		}
+	}()
+
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// MemoryLocker 进程内锁 (单实例部署或测试使用)
+type MemoryLocker struct {
+	mu     sync.Mutex
+	leases map[string]*memoryLease
+}
+
+// memoryLease 进程内租约, 以指针区分每次获取
+type memoryLease struct {
+	expiresAt time.Time
+}
+
+// NewMemoryLocker 创建进程内锁
+func NewMemoryLocker() *MemoryLocker {
+	return &MemoryLocker{
+		leases: make(map[string]*memoryLease),
+	}
+}
+
+// TryLock 尝试获取锁, 持有期间自动续约
+func (l *MemoryLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if lease, ok := l.leases[key]; ok && lease.expiresAt.After(now) {
+		return nil, false, nil
+	}
+
+	lease := &memoryLease{expiresAt: now.Add(ttl)}
+	l.leases[key] = lease
+
+	stop := keepAlive(ttl, func() bool {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		// 租约已过期并被他人重新获取时停止续约
+		if l.leases[key] != lease {
+			return false
+		}
+		lease.expiresAt = time.Now().Add(ttl)
+		return true
+	})
+
+	unlock := func() {
+		stop()
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		// 租约已过期并被他人重新获取时不释放
+		if l.leases[key] == lease {
+			delete(l.leases, key)
+		}
+	}
+	return unlock, true, nil
+}
@@ -0,0 +1,90 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// lockCollection MongoDB 租约集合名称
+const lockCollection = "scheduler_locks"
+
+// MongoLocker 基于 MongoDB 租约文档的分布式锁
+type MongoLocker struct {
+	coll  *mongo.Collection
+	owner string
+}
+
+// NewMongoLocker 创建 MongoDB 分布式锁
+func NewMongoLocker(db *mongo.Database) *MongoLocker {
+	hostname, _ := os.Hostname()
+	return &MongoLocker{
+		coll:  db.Collection(lockCollection),
+		owner: fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), uuid.NewString()),
+	}
+}
+
+// TryLock 尝试获取租约, 持有期间自动续约
+// 租约不存在或已过期时写入成功; 租约被他人持有时 upsert 触发主键冲突, 视为获取失败
+func (l *MongoLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	now := time.Now()
+	filter := bson.M{
+		"_id":        key,
+		"expires_at": bson.M{"$lte": now},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"owner":      l.owner,
+			"expires_at": now.Add(ttl),
+		},
+	}
+
+	_, err := l.coll.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("获取 MongoDB 租约失败: %w", err)
+	}
+
+	stop := keepAlive(ttl, func() bool {
+		return l.renew(key, ttl)
+	})
+
+	unlock := func() {
+		stop()
+		_, err := l.coll.DeleteOne(context.Background(), bson.M{"_id": key, "owner": l.owner})
+		if err != nil {
+			log.Printf("Failed to release lease %s: %s", key, err)
+		}
+	}
+	return unlock, true, nil
+}
+
+// renew 延长租约, 租约已不属于当前实例时返回 false
+// 续约请求失败时返回 true, 在下一个周期重试
+func (l *MongoLocker) renew(key string, ttl time.Duration) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), ttl/3)
+	defer cancel()
+
+	result, err := l.coll.UpdateOne(ctx,
+		bson.M{"_id": key, "owner": l.owner},
+		bson.M{"$set": bson.M{"expires_at": time.Now().Add(ttl)}},
+	)
+	if err != nil {
+		log.Printf("Failed to renew lease %s: %s", key, err)
+		return true
+	}
+	if result.MatchedCount == 0 {
+		log.Printf("Lease %s lost, stop renewing", key)
+		return false
+	}
+	return true
+}
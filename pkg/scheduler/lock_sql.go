@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SQLLocker 基于数据库会话锁的分布式锁
+// MySQL 使用 GET_LOCK, PostgreSQL 使用 advisory lock, 锁随连接释放, 不需要租约
+type SQLLocker struct {
+	db        *sql.DB
+	lockSQL   string
+	unlockSQL string
+	dialect   string
+}
+
+// NewSQLLocker 根据 GORM 连接的数据库类型创建分布式锁
+func NewSQLLocker(db *gorm.DB) (*SQLLocker, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("获取数据库连接失败: %w", err)
+	}
+
+	switch name := db.Dialector.Name(); name {
+	case "mysql":
+		return &SQLLocker{
+			db:        sqlDB,
+			lockSQL:   "SELECT GET_LOCK(?, 0) = 1",
+			unlockSQL: "SELECT RELEASE_LOCK(?)",
+			dialect:   name,
+		}, nil
+	case "postgres":
+		return &SQLLocker{
+			db:        sqlDB,
+			lockSQL:   "SELECT pg_try_advisory_lock(hashtext($1))",
+			unlockSQL: "SELECT pg_advisory_unlock(hashtext($1))",
+			dialect:   name,
+		}, nil
+	default:
+		return nil, fmt.Errorf("不支持的数据库类型: %s", name)
+	}
+}
+
+// TryLock 尝试获取锁, 锁与独占的数据库连接绑定, 释放锁时归还连接
+func (l *SQLLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("获取 %s 连接失败: %w", l.dialect, err)
+	}
+
+	var acquired sql.NullBool
+	if err := conn.QueryRowContext(ctx, l.lockSQL, key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("获取 %s 锁失败: %w", l.dialect, err)
+	}
+	if !acquired.Valid || !acquired.Bool {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		defer conn.Close()
+		if _, err := conn.ExecContext(context.Background(), l.unlockSQL, key); err != nil {
+			log.Printf("Failed to release lock %s: %s", key, err)
+			// 释放失败时丢弃该连接, 避免持有锁的会话回到连接池
+			conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+	}
+	return unlock, true, nil
+}
@@ -0,0 +1,95 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryLocker(t *testing.T) {
+	l := NewMemoryLocker()
+	ctx := context.Background()
+
+	unlock, ok, err := l.TryLock(ctx, "job", time.Minute)
+	if err != nil || !ok {
+		t.Fatalf("first TryLock: ok=%v err=%v", ok, err)
+	}
+	if _, ok, _ := l.TryLock(ctx, "job", time.Minute); ok {
+		t.Fatal("second TryLock acquired a held lock")
+	}
+	if _, ok, _ := l.TryLock(ctx, "other", time.Minute); !ok {
+		t.Fatal("TryLock on a different key failed")
+	}
+
+	unlock()
+	if _, ok, _ := l.TryLock(ctx, "job", time.Minute); !ok {
+		t.Fatal("TryLock after unlock failed")
+	}
+}
+
+func TestMemoryLockerExpiredLease(t *testing.T) {
+	l := NewMemoryLocker()
+	ctx := context.Background()
+
+	unlock, ok, _ := l.TryLock(ctx, "job", time.Minute)
+	if !ok {
+		t.Fatal("TryLock failed")
+	}
+	// 模拟持有者停顿未能续约, 租约过期
+	l.mu.Lock()
+	l.leases["job"].expiresAt = time.Now()
+	l.mu.Unlock()
+
+	// 持有者的租约过期后被他人获取, 持有者再释放时不影响新的持有者
+	unlock2, ok, _ := l.TryLock(ctx, "job", time.Minute)
+	if !ok {
+		t.Fatal("TryLock after expiry failed")
+	}
+	unlock()
+	if _, ok, _ := l.TryLock(ctx, "job", time.Minute); ok {
+		t.Fatal("stale unlock released the new holder's lease")
+	}
+	unlock2()
+}
+
+func TestMemoryLockerRenewsLease(t *testing.T) {
+	l := NewMemoryLocker()
+	ctx := context.Background()
+
+	unlock, ok, _ := l.TryLock(ctx, "job", 30*time.Millisecond)
+	if !ok {
+		t.Fatal("TryLock failed")
+	}
+
+	// 持有时间超过租约时长, 续约后锁仍被持有
+	time.Sleep(100 * time.Millisecond)
+	if _, ok, _ := l.TryLock(ctx, "job", time.Minute); ok {
+		t.Fatal("lease expired while the holder was still running")
+	}
+
+	unlock()
+	unlock()
+	if _, ok, _ := l.TryLock(ctx, "job", time.Minute); !ok {
+		t.Fatal("TryLock after unlock failed")
+	}
+}
+
+func TestSingletonJobLocked(t *testing.T) {
+	savedLocker, savedStore := locker, store
+	t.Cleanup(func() {
+		locker, store = savedLocker, savedStore
+	})
+	SetLocker(NewMemoryLocker())
+	SetRunStore(NewMemoryRunStore(10))
+
+	job := Job{Name: "test-singleton", Singleton: true, Run: func(ctx context.Context) error { return nil }}
+	unlock, ok, _ := locker.TryLock(context.Background(), lockKey(job), time.Minute)
+	if !ok {
+		t.Fatal("TryLock failed")
+	}
+	defer unlock()
+
+	if err := job.execute(context.Background(), TriggerManual); err != ErrJobLocked {
+		t.Fatalf("execute while locked = %v, want ErrJobLocked", err)
+	}
+}
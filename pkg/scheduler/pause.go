@@ -0,0 +1,56 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+)
+
+// PauseStore 任务暂停状态存储
+// 调度器每次触发时读取暂停状态, 多实例部署时需要使用共享存储, 暂停请求才能作用于实际执行任务的实例
+type PauseStore interface {
+	// SetPaused 设置任务的暂停状态
+	SetPaused(ctx context.Context, name string, paused bool) error
+	// IsPaused 任务是否已暂停
+	IsPaused(ctx context.Context, name string) (bool, error)
+}
+
+var pauses PauseStore = NewMemoryPauseStore()
+
+// SetPauseStore 设置任务暂停状态存储
+func SetPauseStore(s PauseStore) {
+	if s != nil {
+		pauses = s
+	}
+}
+
+// MemoryPauseStore 进程内暂停状态 (未配置数据库时使用, 仅对当前进程生效, 重启后丢失)
+type MemoryPauseStore struct {
+	mu     sync.RWMutex
+	paused map[string]bool
+}
+
+// NewMemoryPauseStore 创建进程内暂停状态存储
+func NewMemoryPauseStore() *MemoryPauseStore {
+	return &MemoryPauseStore{
+		paused: make(map[string]bool),
+	}
+}
+
+// SetPaused 设置任务的暂停状态
+func (s *MemoryPauseStore) SetPaused(ctx context.Context, name string, paused bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if paused {
+		s.paused[name] = true
+	} else {
+		delete(s.paused, name)
+	}
+	return nil
+}
+
+// IsPaused 任务是否已暂停
+func (s *MemoryPauseStore) IsPaused(ctx context.Context, name string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.paused[name], nil
+}
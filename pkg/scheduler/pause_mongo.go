@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoPauseStore 基于 MongoDB 的暂停状态存储 (job_pauses 集合), 所有实例共享
+type MongoPauseStore struct {
+	coll *mongo.Collection
+}
+
+// NewMongoPauseStore 创建 MongoDB 暂停状态存储
+func NewMongoPauseStore(db *mongo.Database) *MongoPauseStore {
+	return &MongoPauseStore{coll: db.Collection(JobPause{}.TableName())}
+}
+
+// SetPaused 设置任务的暂停状态
+func (s *MongoPauseStore) SetPaused(ctx context.Context, name string, paused bool) error {
+	if !paused {
+		_, err := s.coll.DeleteOne(ctx, bson.M{"_id": name})
+		return err
+	}
+	_, err := s.coll.UpdateOne(ctx,
+		bson.M{"_id": name},
+		bson.M{"$setOnInsert": bson.M{"paused_at": time.Now()}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// IsPaused 任务是否已暂停
+func (s *MongoPauseStore) IsPaused(ctx context.Context, name string) (bool, error) {
+	count, err := s.coll.CountDocuments(ctx, bson.M{"_id": name})
+	return count > 0, err
+}
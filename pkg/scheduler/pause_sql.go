@@ -0,0 +1,49 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/plugin/dbresolver"
+)
+
+// JobPause 已暂停的任务 (job_pauses 表, 由 db/migrations 创建)
+type JobPause struct {
+	JobName  string    `gorm:"type:varchar(100);primaryKey"`
+	PausedAt time.Time `gorm:"not null"`
+}
+
+// TableName 表名
+func (JobPause) TableName() string {
+	return "job_pauses"
+}
+
+// GormPauseStore 基于 GORM 的暂停状态存储, 所有实例共享
+type GormPauseStore struct {
+	db *gorm.DB
+}
+
+// NewGormPauseStore 创建 GORM 暂停状态存储
+func NewGormPauseStore(db *gorm.DB) *GormPauseStore {
+	return &GormPauseStore{db: db}
+}
+
+// SetPaused 设置任务的暂停状态
+func (s *GormPauseStore) SetPaused(ctx context.Context, name string, paused bool) error {
+	db := s.db.WithContext(ctx)
+	if !paused {
+		return db.Delete(&JobPause{}, "job_name = ?", name).Error
+	}
+	return db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&JobPause{JobName: name, PausedAt: time.Now()}).Error
+}
+
+// IsPaused 任务是否已暂停 (读主库, 暂停后立即生效)
+func (s *GormPauseStore) IsPaused(ctx context.Context, name string) (bool, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Clauses(dbresolver.Write).
+		Model(&JobPause{}).Where("job_name = ?", name).Count(&count).Error
+	return count > 0, err
+}
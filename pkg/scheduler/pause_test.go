@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMemoryPauseStore(t *testing.T) {
+	s := NewMemoryPauseStore()
+	ctx := context.Background()
+
+	if paused, _ := s.IsPaused(ctx, "job"); paused {
+		t.Fatal("new store reports job paused")
+	}
+	if err := s.SetPaused(ctx, "job", true); err != nil {
+		t.Fatal(err)
+	}
+	if paused, _ := s.IsPaused(ctx, "job"); !paused {
+		t.Fatal("job not paused after SetPaused(true)")
+	}
+	if err := s.SetPaused(ctx, "job", false); err != nil {
+		t.Fatal(err)
+	}
+	if paused, _ := s.IsPaused(ctx, "job"); paused {
+		t.Fatal("job still paused after SetPaused(false)")
+	}
+}
+
+func TestPauseRequiresSharedStoreOrScheduledJob(t *testing.T) {
+	saved, savedPauses := registeredJobs, pauses
+	t.Cleanup(func() {
+		registeredJobs, pauses = saved, savedPauses
+		Scheduler = nil
+		controlMu.Lock()
+		clear(entryIDs)
+		controlMu.Unlock()
+	})
+	registeredJobs = nil
+	SetPauseStore(NewMemoryPauseStore())
+	Register(Job{Name: "test-pause", Spec: "@every 1h", Run: func(ctx context.Context) error { return nil }})
+	ctx := context.Background()
+
+	if err := Pause(ctx, "missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("Pause unknown job: err=%v, want ErrJobNotFound", err)
+	}
+
+	// 暂停状态仅保存在内存中, 任务未在当前进程调度时暂停不会生效
+	if err := Pause(ctx, "test-pause"); !errors.Is(err, ErrJobNotScheduled) {
+		t.Fatalf("Pause unscheduled job: err=%v, want ErrJobNotScheduled", err)
+	}
+
+	if err := Init(Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadJobs(); err != nil {
+		t.Fatal(err)
+	}
+	if err := Pause(ctx, "test-pause"); err != nil {
+		t.Fatalf("Pause scheduled job: %v", err)
+	}
+	if st, _ := Status(ctx, "test-pause"); !st.Paused || !st.Scheduled {
+		t.Fatalf("status = %+v, want paused and scheduled", st)
+	}
+	if err := Resume(ctx, "test-pause"); err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+
+	// 共享存储 (数据库) 中的暂停状态对所有进程生效, 不要求任务在当前进程调度
+	Scheduler = nil
+	SetPauseStore(sharedPauseStore{NewMemoryPauseStore()})
+	if err := Pause(ctx, "test-pause"); err != nil {
+		t.Fatalf("Pause with shared store: %v", err)
+	}
+	if paused, _ := IsPaused(ctx, "test-pause"); !paused {
+		t.Fatal("job not paused in shared store")
+	}
+}
+
+// sharedPauseStore 模拟数据库等共享的暂停状态存储
+type sharedPauseStore struct {
+	*MemoryPauseStore
+}
@@ -1,6 +1,7 @@
 package scheduler
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -10,21 +11,65 @@ import (
 var (
 	// Scheduler 全局调度器实例
 	Scheduler *cron.Cron
+
+	// location 调度器时区
+	location *time.Location
+
+	// parser Cron 表达式解析器 (与 cron.New 默认解析器一致)
+	parser = standardParser
+)
+
+var (
+	// standardParser 标准 5 段表达式 (分 时 日 月 周)
+	standardParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+	// secondsParser 秒级表达式, 秒字段可选 (兼容 5 段表达式)
+	secondsParser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
 )
 
+// Options 调度器配置
+type Options struct {
+	Timezone string // 默认时区, 任务可通过 "CRON_TZ=Asia/Tokyo 0 9 * * *" 单独指定
+	Seconds  bool   // 是否启用秒级表达式 (6 段, 首段为秒)
+}
+
 // Init 初始化调度器
-func Init() error {
-	// 创建调度器 (使用中国时区)
-	location, err := time.LoadLocation("Asia/Shanghai")
+func Init(opts Options) error {
+	if opts.Timezone == "" {
+		opts.Timezone = "Asia/Shanghai"
+	}
+
+	loc, err := time.LoadLocation(opts.Timezone)
 	if err != nil {
 		return fmt.Errorf("加载时区失败: %w", err)
 	}
+	location = loc
+
+	parser = standardParser
+	if opts.Seconds {
+		parser = secondsParser
+	}
+
+	Scheduler = cron.New(cron.WithLocation(location), cron.WithParser(parser))
+	fmt.Printf("✅ Scheduler: 已初始化 (时区: %s, 秒级表达式: %v)\n", opts.Timezone, opts.Seconds)
+	return nil
+}
 
-	Scheduler = cron.New(cron.WithLocation(location))
-	fmt.Println("✅ Scheduler: 已初始化 (时区: Asia/Shanghai)")
+// ValidateSpec 使用当前解析器校验 Cron 表达式
+func ValidateSpec(spec string) error {
+	if _, err := parser.Parse(spec); err != nil {
+		return fmt.Errorf("无效的 Cron 表达式 [%s]: %w", spec, err)
+	}
 	return nil
 }
 
+// Location 返回调度器时区 (未初始化时使用本地时区)
+func Location() *time.Location {
+	if location == nil {
+		return time.Local
+	}
+	return location
+}
+
 // Start 启动调度器
 func Start() {
 	if Scheduler != nil {
@@ -41,6 +86,20 @@ func Stop() {
 	}
 }
 
+// Shutdown 停止调度器, 并等待正在运行的任务执行完成
+func Shutdown(ctx context.Context) error {
+	if Scheduler == nil {
+		return nil
+	}
+
+	select {
+	case <-Scheduler.Stop().Done():
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("等待运行中的任务超时: %w", ctx.Err())
+	}
+}
+
 // AddJob 添加定时任务
 func AddJob(spec string, cmd func()) (cron.EntryID, error) {
 	if Scheduler == nil {